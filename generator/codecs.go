@@ -0,0 +1,211 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for the codecs file
+type CodecDef struct {
+	Name string
+	Expr string
+}
+
+type CodecsTemplateData struct {
+	Codecs []CodecDef
+}
+
+// codecContext tracks where a codec expression is rendered
+type codecContext struct {
+	config Config
+	// needs holds the declarations that contain mapped values
+	needs map[string]bool
+	// prefix is prepended to codec names ("Codecs." outside of the codecs file)
+	prefix  string
+	order   map[string]int
+	current int
+}
+
+// Find the declarations that contain values needing conversion, directly or through references
+func findSchemasNeedingCodecs(api *ir.API, config Config) map[string]bool {
+	needs := make(map[string]bool)
+
+	// Direct conversions first, then propagate through references until nothing changes
+	for _, decl := range api.Types {
+		if typeHasDirectCodec(decl.Type, config) {
+			needs[decl.Name] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range api.Types {
+			if needs[decl.Name] {
+				continue
+			}
+			for _, ref := range typeRefs(decl.Type) {
+				if needs[ref] {
+					needs[decl.Name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	return needs
+}
+
+// Report whether a type (without following references) contains a value needing conversion or a
+// field renamed on the wire
+func typeHasDirectCodec(t *ir.Type, config Config) bool {
+	found := hasRenamedFields(t, config)
+	ir.Walk(t, func(t *ir.Type) {
+		if codecForType(t, config) != "" {
+			found = true
+		}
+	})
+	return found
+}
+
+// Generate codecs for declarations containing mapped values such as Date or bigint
+func generateCodecs(api *ir.API, config Config, needs map[string]bool) error {
+	runtimeTmpl, err := loadTemplate("utils/codec.tmpl")
+	if err != nil {
+		return err
+	}
+	runtimeContent, err := executeTemplate(runtimeTmpl, struct{}{})
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(config.OutputPath, "utils", "codec.ts"), runtimeContent); err != nil {
+		return err
+	}
+
+	decls := make(map[string]*ir.Decl)
+	for _, decl := range api.Types {
+		if needs[decl.Name] {
+			decls[decl.Name] = decl
+		}
+	}
+
+	ordered, order := orderDeclsByDependency(decls)
+	data := CodecsTemplateData{}
+	for i, name := range ordered {
+		ctx := &codecContext{config: config, needs: needs, order: order, current: i}
+		data.Codecs = append(data.Codecs, CodecDef{
+			Name: codecName(name),
+			Expr: typeToCodec(decls[name].Type, ctx, 0),
+		})
+	}
+
+	tmpl, err := loadTemplate("codecs.tmpl")
+	if err != nil {
+		return err
+	}
+	content, err := executeTemplate(tmpl, data)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(config.OutputPath, "codecs", "index.ts"), content)
+}
+
+// Report whether any operation converts its request or response with a codec
+func operationsUseCodecs(api *ir.API, config Config) bool {
+	codecNeeds := findSchemasNeedingCodecs(api, config)
+	for _, resource := range api.Resources {
+		for _, operation := range resourceMethods(resource, config, codecNeeds) {
+			if operation.RequestCodec != "" || operation.ResponseCodec != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func codecName(typeName string) string {
+	return typeName + "Codec"
+}
+
+// Convert a type to a codec expression, or "" when its values are sent as-is
+func typeToCodec(t *ir.Type, ctx *codecContext, indent int) string {
+	if t == nil {
+		return ""
+	}
+
+	if t.Kind == ir.Ref {
+		if !ctx.needs[t.Ref] {
+			return ""
+		}
+		ref := ctx.prefix + codecName(t.Ref)
+		if ctx.order != nil {
+			if pos, ok := ctx.order[t.Ref]; !ok || pos >= ctx.current {
+				return fmt.Sprintf("c.lazy(() => %s)", ref)
+			}
+		}
+		return ref
+	}
+
+	if codec := codecForType(t, ctx.config); codec != "" {
+		return codec
+	}
+
+	switch t.Kind {
+	case ir.Intersection:
+		var parts []string
+		for _, variant := range t.Variants {
+			if expr := typeToCodec(variant, ctx, indent); expr != "" {
+				parts = append(parts, expr)
+			}
+		}
+		switch len(parts) {
+		case 0:
+			return ""
+		case 1:
+			return parts[0]
+		default:
+			return fmt.Sprintf("c.all(%s)", strings.Join(parts, ", "))
+		}
+	case ir.Array:
+		if item := typeToCodec(t.Items, ctx, indent); item != "" {
+			return fmt.Sprintf("c.array(%s)", item)
+		}
+		return ""
+	case ir.Object:
+	default:
+		return ""
+	}
+
+	pad := strings.Repeat("  ", indent+1)
+	names := fieldNames(t.Fields, ctx.config)
+	var entries, renames []string
+	for _, f := range t.Fields {
+		if expr := typeToCodec(f.Type, ctx, indent+1); expr != "" {
+			entries = append(entries, fmt.Sprintf("%s%s: %s,", pad, propertyKey(names[f.Name]), expr))
+		}
+		if names[f.Name] != f.Name {
+			renames = append(renames, fmt.Sprintf("%s: %s", propertyKey(names[f.Name]), jsString(f.Name)))
+		}
+	}
+	if len(entries) == 0 && len(renames) == 0 {
+		return ""
+	}
+
+	fields := "{}"
+	if len(entries) > 0 {
+		fields = "{\n" + strings.Join(entries, "\n") + "\n" + strings.Repeat("  ", indent) + "}"
+	}
+	if len(renames) == 0 {
+		return "c.object(" + fields + ")"
+	}
+	// The second argument maps TypeScript property names to their wire names
+	return fmt.Sprintf("c.object(%s, { %s })", fields, strings.Join(renames, ", "))
+}
+
+// Build the codec expression for an operation's request or response type, or "" if none is needed
+func getOperationCodec(t *ir.Type, config Config, needs map[string]bool) string {
+	return typeToCodec(t, &codecContext{config: config, needs: needs, prefix: "Codecs."}, 0)
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Collect the JSDoc lines describing a type: description, format, constraints, default, example
+// and deprecation
+func typeDocLines(t *ir.Type) []string {
+	if t == nil || t.Kind == ir.Ref {
+		return nil
+	}
+
+	var lines []string
+	if description := strings.TrimSpace(t.Description); description != "" {
+		lines = append(lines, splitDocText(description)...)
+	}
+	if t.Format != "" {
+		lines = append(lines, "@format "+t.Format)
+	}
+	if t.Minimum != nil {
+		lines = append(lines, "@minimum "+formatNumber(*t.Minimum))
+	}
+	if t.Maximum != nil {
+		lines = append(lines, "@maximum "+formatNumber(*t.Maximum))
+	}
+	if t.MinLength != nil {
+		lines = append(lines, fmt.Sprintf("@minLength %d", *t.MinLength))
+	}
+	if t.MaxLength != nil {
+		lines = append(lines, fmt.Sprintf("@maxLength %d", *t.MaxLength))
+	}
+	if t.MinItems != nil {
+		lines = append(lines, fmt.Sprintf("@minItems %d", *t.MinItems))
+	}
+	if t.MaxItems != nil {
+		lines = append(lines, fmt.Sprintf("@maxItems %d", *t.MaxItems))
+	}
+	if t.Pattern != "" {
+		lines = append(lines, "@pattern "+t.Pattern)
+	}
+	if t.Default != nil {
+		lines = append(lines, "@default "+jsLiteral(t.Default))
+	}
+	if t.Example != nil {
+		lines = append(lines, "@example "+jsLiteral(t.Example))
+	}
+	if t.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	return lines
+}
+
+// Render lines as a JSDoc comment at the given indentation, or "" when there is nothing to say
+func docComment(lines []string, indent string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = escapeDocText(strings.TrimRight(line, " \t\r"))
+	}
+
+	if len(escaped) == 1 {
+		return fmt.Sprintf("%s/** %s */", indent, escaped[0])
+	}
+
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range escaped {
+		if line == "" {
+			b.WriteString(indent + " *\n")
+		} else {
+			b.WriteString(indent + " * " + line + "\n")
+		}
+	}
+	b.WriteString(indent + " */")
+	return b.String()
+}
+
+// Build the `<Name>Constraints` metadata object for an object type. Keys use HTML input attribute
+// names so entries can be spread onto form fields; "" is returned when no property has constraints.
+func constraintsObject(t *ir.Type, config Config) string {
+	names := fieldNames(t.Fields, config)
+	var entries []string
+	for _, field := range t.Fields {
+		attrs := propertyConstraintAttrs(field.Type, field.Required)
+		if len(attrs) > 0 {
+			entries = append(entries, fmt.Sprintf("  %s: { %s },", propertyKey(names[field.Name]), strings.Join(attrs, ", ")))
+		}
+	}
+
+	if len(entries) == 0 {
+		return ""
+	}
+	return "{\n" + strings.Join(entries, "\n") + "\n}"
+}
+
+func propertyConstraintAttrs(t *ir.Type, required bool) []string {
+	var attrs []string
+	if required {
+		attrs = append(attrs, "required: true")
+	}
+	if t == nil || t.Kind == ir.Ref {
+		return attrs
+	}
+	if t.Minimum != nil {
+		attrs = append(attrs, "min: "+formatNumber(*t.Minimum))
+	}
+	if t.Maximum != nil {
+		attrs = append(attrs, "max: "+formatNumber(*t.Maximum))
+	}
+	if t.MinLength != nil {
+		attrs = append(attrs, fmt.Sprintf("minlength: %d", *t.MinLength))
+	}
+	if t.MaxLength != nil {
+		attrs = append(attrs, fmt.Sprintf("maxlength: %d", *t.MaxLength))
+	}
+	if t.Pattern != "" {
+		attrs = append(attrs, "pattern: "+jsString(t.Pattern))
+	}
+	return attrs
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
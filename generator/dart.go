@@ -0,0 +1,875 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for the Dart client
+type DartClientTemplateData struct {
+	DefaultBaseURL string
+	TimeoutMs      int
+	Resources      []DartResourceDef
+}
+
+type DartModelsTemplateData struct {
+	Classes []DartClass
+	Enums   []DartEnum
+	Aliases []DartAlias
+}
+
+type DartResourceTemplateData struct {
+	Resource string
+	Class    string
+	Models   bool
+	Methods  []DartMethod
+}
+
+type DartResourceDef struct {
+	// Resource is the resource name; Class and Field name its client like the TypeScript client
+	// (PetApiClient, api.pet), and File is its file in resources/
+	Resource string
+	Class    string
+	Field    string
+	File     string
+}
+
+type DartClass struct {
+	Name   string
+	Doc    string
+	Fields []DartField
+}
+
+type DartField struct {
+	Doc      string
+	Name     string
+	Type     string
+	Required bool
+	// FromJson reads the field from json; ToJson is its entry in the toJson map
+	FromJson string
+	ToJson   string
+}
+
+type DartEnum struct {
+	Name    string
+	Doc     string
+	Base    string
+	Members []DartMember
+}
+
+type DartMember struct {
+	Doc   string
+	Name  string
+	Value string
+	// Separator ends the member: "," or ";" after the last one
+	Separator string
+}
+
+// DartAlias is a typedef (lists, maps, unions, scalars)
+type DartAlias struct {
+	Doc  string
+	Name string
+	Type string
+}
+
+type DartMethod struct {
+	Name       string
+	Doc        string
+	Params     string
+	ReturnType string
+	// Args are the arguments of the ApiHttp.send call, and Result converts its json result
+	Args   string
+	Result string
+}
+
+// How a Dart type converts from and to JSON
+type dartKind int
+
+const (
+	dartAny dartKind = iota
+	// dartScalar types (String, bool) are cast
+	dartScalar
+	dartInt
+	dartDouble
+	dartDateTime
+	dartDate
+	dartClass
+	dartEnum
+	dartList
+	dartMap
+)
+
+// dartType is a resolved Dart type. References to typedefs keep the typedef name in alias and
+// convert like the aliased type.
+type dartType struct {
+	kind     dartKind
+	name     string
+	alias    string
+	nullable bool
+	elem     *dartType
+}
+
+// dartModel maps the API's types to Dart types
+type dartModel struct {
+	decls map[string]*ir.Decl
+	// names maps declaration names to Dart names, and kinds tells how each is declared
+	names   map[string]string
+	kinds   map[string]dartKind
+	classes map[string]DartClass
+	enums   map[string]DartEnum
+	aliases map[string]DartAlias
+	// aliasTypes caches resolved typedefs; resolving marks those being resolved, to break cycles
+	aliasTypes map[string]*dartType
+	resolving  map[string]bool
+	taken      map[string]bool
+	// refs collects whether a resource refers to the models, for its imports
+	refs bool
+}
+
+// Reserved words and built-in identifiers, which Dart rejects as some or all names
+var dartKeywords = map[string]bool{
+	"abstract": true, "as": true, "assert": true, "async": true, "await": true, "base": true,
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"covariant": true, "default": true, "deferred": true, "do": true, "dynamic": true, "else": true,
+	"enum": true, "export": true, "extends": true, "extension": true, "external": true,
+	"factory": true, "false": true, "final": true, "finally": true, "for": true, "Function": true,
+	"get": true, "hide": true, "if": true, "implements": true, "import": true, "in": true,
+	"interface": true, "is": true, "late": true, "library": true, "mixin": true, "new": true,
+	"null": true, "on": true, "operator": true, "part": true, "required": true, "rethrow": true,
+	"return": true, "sealed": true, "set": true, "show": true, "static": true, "super": true,
+	"switch": true, "sync": true, "this": true, "throw": true, "true": true, "try": true,
+	"typedef": true, "var": true, "void": true, "when": true, "while": true, "with": true,
+	"yield": true,
+}
+
+// Names the generated libraries use or that would shadow dart:core types they need
+var dartReservedTypeNames = []string{
+	"String", "int", "double", "num", "bool", "Object", "Null", "Never", "List", "Map", "Set",
+	"Iterable", "DateTime", "Duration", "Uri", "Future", "Stream", "MapEntry", "Function", "Type",
+	"Symbol", "Enum", "Record", "Exception", "Error", "ArgumentError", "ApiClient", "ApiException",
+	"ApiHttp",
+}
+
+// Member names of the generated classes, which fields and methods must not take; enums also have
+// values, index, name and value
+var dartReservedMembers = []string{"hashCode", "runtimeType", "toString", "noSuchMethod", "fromJson", "toJson"}
+
+var dartReservedEnumMembers = append([]string{"values", "index", "name", "value"}, dartReservedMembers...)
+
+var dartLeadingUnderscores = regexp.MustCompile(`^_+`)
+
+// GenerateDart generates a Dart client library from the spec
+func GenerateDart(config Config) (*Report, error) {
+	api, report, err := prepareSpec(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(config.OutputPath, "resources"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := generateDartClient(api, config); err != nil {
+		return nil, err
+	}
+
+	report.Deprecated = collectDeprecations(api)
+	return report, nil
+}
+
+func generateDartClient(api *ir.API, config Config) error {
+	model := newDartModel(api)
+
+	resourceTmpl, err := loadTemplate("dart/resource.tmpl")
+	if err != nil {
+		return err
+	}
+
+	// Resources are named like the TypeScript client's, from the same grouping
+	var resources []DartResourceDef
+	fields := make(map[string]bool)
+	for _, resource := range api.Resources {
+		lower := strings.ToLower(resource.Name)
+		def := DartResourceDef{
+			Resource: resource.Name,
+			Class:    model.reserve(dartTypeName(toTitleCase(resource.Name) + "ApiClient")),
+			Field:    uniqueName(dartClientName(lower), func(n string) bool { return fields[n] }),
+			File:     fileSegment(lower) + "_api.dart",
+		}
+		fields[def.Field] = true
+		resources = append(resources, def)
+	}
+	for _, decl := range api.Types {
+		model.names[decl.Name] = model.reserve(dartTypeName(decl.Name))
+	}
+	for _, decl := range api.Types {
+		model.kinds[decl.Name] = model.declKind(decl.Type)
+	}
+
+	for i, resource := range api.Resources {
+		def := resources[i]
+		data := DartResourceTemplateData{Resource: resource.Name, Class: def.Class}
+		model.refs = false
+		methodNames := make(map[string]bool)
+		for _, name := range dartReservedMembers {
+			methodNames[name] = true
+		}
+		for _, op := range resource.Operations {
+			name := uniqueName(dartClientName(op.ID), func(n string) bool { return methodNames[n] })
+			methodNames[name] = true
+			data.Methods = append(data.Methods, model.method(name, op))
+		}
+		data.Models = model.refs
+
+		filePath, err := outputPath(filepath.Join(config.OutputPath, "resources"), def.File)
+		if err != nil {
+			return err
+		}
+		if err := writeDartFile(resourceTmpl, data, filePath); err != nil {
+			return err
+		}
+	}
+
+	// Types are collected while generating the resources, which can hoist inline schemas
+	for _, decl := range api.Types {
+		model.declare(decl)
+	}
+	modelsData := DartModelsTemplateData{}
+	for _, name := range sortedKeys(model.classes) {
+		modelsData.Classes = append(modelsData.Classes, model.classes[name])
+	}
+	for _, name := range sortedKeys(model.enums) {
+		modelsData.Enums = append(modelsData.Enums, model.enums[name])
+	}
+	for _, name := range sortedKeys(model.aliases) {
+		modelsData.Aliases = append(modelsData.Aliases, model.aliases[name])
+	}
+
+	timeout := 10000
+	if ms, err := strconv.Atoi(config.Timeout); err == nil && ms > 0 {
+		timeout = ms
+	}
+	title := "the API"
+	if lines := splitDocText(api.Title); len(lines) > 0 {
+		title = lines[0]
+	}
+	files := map[string]any{
+		"models.tmpl":  modelsData,
+		"runtime.tmpl": nil,
+		"client.tmpl": DartClientTemplateData{
+			DefaultBaseURL: dartString(defaultBaseURL(api, config)),
+			TimeoutMs:      timeout,
+			Resources:      resources,
+		},
+		"api.tmpl": struct {
+			Title     string
+			Resources []DartResourceDef
+		}{title, resources},
+	}
+	for _, name := range sortedKeys(files) {
+		tmpl, err := loadTemplate("dart/" + name)
+		if err != nil {
+			return err
+		}
+		fileName := strings.TrimSuffix(name, ".tmpl") + ".dart"
+		if err := writeDartFile(tmpl, files[name], filepath.Join(config.OutputPath, fileName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render a Dart template, collapsing the blank lines left by empty sections
+func writeDartFile(tmpl *template.Template, data any, path string) error {
+	content, err := executeTemplate(tmpl, data)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, dartBlankLines.ReplaceAllString(content, "\n\n"))
+}
+
+var dartBlankLines = regexp.MustCompile(`\n{3,}`)
+
+func newDartModel(api *ir.API) *dartModel {
+	m := &dartModel{
+		decls:      declsByName(api),
+		names:      make(map[string]string),
+		kinds:      make(map[string]dartKind),
+		classes:    make(map[string]DartClass),
+		enums:      make(map[string]DartEnum),
+		aliases:    make(map[string]DartAlias),
+		aliasTypes: make(map[string]*dartType),
+		resolving:  make(map[string]bool),
+		taken:      make(map[string]bool),
+	}
+	for _, name := range dartReservedTypeNames {
+		m.taken[name] = true
+	}
+	return m
+}
+
+// Take a library-level name, suffixed until it is unique
+func (m *dartModel) reserve(name string) string {
+	name = uniqueName(name, func(n string) bool { return m.taken[n] })
+	m.taken[name] = true
+	return name
+}
+
+// Get how a declaration is declared: as a class, an enum, or else a typedef (dartAny)
+func (m *dartModel) declKind(t *ir.Type) dartKind {
+	if len(t.Enum) > 0 {
+		if dartEnumBase(t) != "" && len(dartEnumMembers(t)) > 0 {
+			return dartEnum
+		}
+		return dartAny
+	}
+	switch t.Kind {
+	case ir.Object:
+		return dartClass
+	case ir.Intersection:
+		if _, ok := flattenFields(t, m.decls, make(map[string]bool)); ok {
+			return dartClass
+		}
+	}
+	return dartAny
+}
+
+func (m *dartModel) declare(decl *ir.Decl) {
+	name := m.names[decl.Name]
+	t := decl.Type
+	switch m.kinds[decl.Name] {
+	case dartClass:
+		fields, _ := flattenFields(t, m.decls, map[string]bool{decl.Name: true})
+		m.classes[name] = m.class(name, t.Description, fields)
+	case dartEnum:
+		m.enums[name] = dartEnumDecl(name, t)
+	default:
+		m.aliases[name] = DartAlias{
+			Doc:  dartDoc(t.Description, ""),
+			Name: name,
+			Type: dartTypeString(m.aliasedType(decl.Name)),
+		}
+	}
+}
+
+// Build a class with a const constructor taking named fields, fromJson and toJson. Fields that
+// are optional in the spec are nullable and left out of toJson when null.
+func (m *dartModel) class(name, description string, fields []*ir.Field) DartClass {
+	class := DartClass{Name: name, Doc: dartDoc(description, "")}
+	taken := map[string]bool{name: true}
+	for _, member := range dartReservedMembers {
+		taken[member] = true
+	}
+	wireNames := make([]string, 0, len(fields))
+	for _, f := range fields {
+		wireNames = append(wireNames, f.Name)
+	}
+	names := memberNames(wireNames, dartMemberName, taken)
+	for _, f := range fields {
+		fieldName := names[f.Name]
+		fieldType := m.resolve(name, dartTitle(f.Name), f.Type)
+		if !f.Required {
+			fieldType = dartNullable(fieldType)
+		}
+		wire := dartString(f.Name)
+		field := DartField{
+			Doc:      dartDoc(f.Type.Description, "  "),
+			Name:     fieldName,
+			Type:     dartTypeString(fieldType),
+			Required: f.Required,
+			FromJson: dartFromJson(fieldType, "json["+wire+"]", 0),
+			ToJson:   wire + ": " + dartToJson(fieldType, fieldName, 0),
+		}
+		if !f.Required {
+			field.ToJson = "if (" + fieldName + " != null) " + field.ToJson
+		}
+		if f.Type.Deprecated {
+			field.Doc = strings.TrimLeft(field.Doc+"\n  @Deprecated('Deprecated in the API specification')", "\n")
+		}
+		class.Fields = append(class.Fields, field)
+	}
+	return class
+}
+
+// Resolve the Dart type of a type. Inline objects and enums are hoisted into declarations named
+// after their owner and field.
+func (m *dartModel) resolve(owner, field string, t *ir.Type) *dartType {
+	if t == nil {
+		return &dartType{kind: dartAny}
+	}
+	resolved := m.resolveBase(owner, field, t)
+	if t.Nullable {
+		return dartNullable(resolved)
+	}
+	return resolved
+}
+
+func (m *dartModel) resolveBase(owner, field string, t *ir.Type) *dartType {
+	if t.Kind == ir.Ref {
+		decl := m.decls[t.Ref]
+		if decl == nil {
+			return &dartType{kind: dartAny}
+		}
+		m.refs = true
+		resolved := &dartType{kind: m.kinds[t.Ref], name: m.names[t.Ref], nullable: decl.Type.Nullable}
+		if resolved.kind == dartAny {
+			resolved = m.aliasType(t.Ref)
+		}
+		return resolved
+	}
+	if len(t.Enum) > 0 && dartEnumBase(t) != "" && len(dartEnumMembers(t)) > 0 {
+		name := m.reserve(owner + field)
+		m.refs = true
+		m.enums[name] = dartEnumDecl(name, t)
+		return &dartType{kind: dartEnum, name: name}
+	}
+
+	switch t.Kind {
+	case ir.String:
+		switch t.Format {
+		case "date-time":
+			return &dartType{kind: dartDateTime, name: "DateTime"}
+		case "date":
+			return &dartType{kind: dartDate, name: "DateTime"}
+		}
+		return &dartType{kind: dartScalar, name: "String"}
+	case ir.Integer:
+		return &dartType{kind: dartInt, name: "int"}
+	case ir.Number:
+		return &dartType{kind: dartDouble, name: "double"}
+	case ir.Boolean:
+		return &dartType{kind: dartScalar, name: "bool"}
+	case ir.Array:
+		return &dartType{kind: dartList, elem: m.resolve(owner, field+"Item", t.Items)}
+	case ir.Map:
+		return &dartType{kind: dartMap, elem: m.resolve(owner, field+"Value", t.Values)}
+	case ir.Union, ir.Intersection:
+		if len(t.Variants) == 1 {
+			return m.resolve(owner, field, t.Variants[0])
+		}
+		if t.Kind == ir.Intersection {
+			if fields, ok := flattenFields(t, m.decls, make(map[string]bool)); ok {
+				return m.hoist(owner+field, t.Description, fields)
+			}
+		}
+	case ir.Object:
+		return m.hoist(owner+field, t.Description, t.Fields)
+	}
+	// Unions have no Dart counterpart and are left as decoded JSON
+	return &dartType{kind: dartAny}
+}
+
+// Declare an inline object as a class
+func (m *dartModel) hoist(name, description string, fields []*ir.Field) *dartType {
+	name = m.reserve(name)
+	m.refs = true
+	m.classes[name] = m.class(name, description, fields)
+	return &dartType{kind: dartClass, name: name}
+}
+
+// Refer to a typedef: its name, converted like the type it stands for
+func (m *dartModel) aliasType(declName string) *dartType {
+	aliased := *m.aliasedType(declName)
+	aliased.alias = m.names[declName]
+	return &aliased
+}
+
+// Resolve the type a typedef stands for. A typedef referring to itself resolves to Object?, as
+// Dart typedefs cannot be recursive.
+func (m *dartModel) aliasedType(declName string) *dartType {
+	if resolved, ok := m.aliasTypes[declName]; ok {
+		return resolved
+	}
+	if m.resolving[declName] {
+		return &dartType{kind: dartAny}
+	}
+	m.resolving[declName] = true
+	refs := m.refs
+	resolved := m.resolve(m.names[declName], "", m.decls[declName].Type)
+	m.refs = refs
+	delete(m.resolving, declName)
+	m.aliasTypes[declName] = resolved
+	return resolved
+}
+
+// Generate a resource method for an operation. Path parameters are positional; query and header
+// parameters and the body are named, optional ones nullable.
+func (m *dartModel) method(name string, op *ir.Operation) DartMethod {
+	owner := dartTypeName(dartTitle(op.ID))
+	taken := map[string]bool{"json": true, "body": true}
+	pathArgs := make(map[string]string)
+	var positional, required, optional, query, headers []string
+
+	for _, param := range op.Params {
+		if param.In != "path" && param.In != "query" && param.In != "header" {
+			continue
+		}
+		argName := uniqueName(dartMemberName(param.Name), func(n string) bool { return taken[n] })
+		taken[argName] = true
+		argType := m.resolve(owner, dartTitle(param.Name), param.Type)
+		if param.In == "path" {
+			pathArgs[param.Name] = dartToJson(argType, argName, 0)
+			positional = append(positional, dartTypeString(argType)+" "+argName)
+			continue
+		}
+		if !param.Required {
+			argType = dartNullable(argType)
+		}
+		entry := dartString(param.Name) + ": " + dartToJson(argType, argName, 0)
+		if param.In == "query" {
+			query = append(query, entry)
+		} else {
+			headers = append(headers, entry)
+		}
+		if param.Required {
+			required = append(required, "required "+dartTypeString(argType)+" "+argName)
+		} else {
+			optional = append(optional, dartTypeString(argType)+" "+argName)
+		}
+	}
+
+	args := []string{dartString(strings.ToUpper(op.Method)), dartPathExpression(op.Path, pathArgs)}
+	if len(query) > 0 {
+		args = append(args, "query: {"+strings.Join(query, ", ")+"}")
+	}
+	if len(headers) > 0 {
+		args = append(args, "headers: {"+strings.Join(headers, ", ")+"}")
+	}
+	// Only JSON bodies are sent; form and multipart bodies are not supported
+	if op.Body != nil && op.Body.IsJSON() && op.Body.Type != nil {
+		bodyType := m.resolve(owner, "Body", op.Body.Type)
+		if op.Body.Required {
+			required = append([]string{"required " + dartTypeString(bodyType) + " body"}, required...)
+		} else {
+			bodyType = dartNullable(bodyType)
+			optional = append([]string{dartTypeString(bodyType) + " body"}, optional...)
+		}
+		args = append(args, "body: "+dartToJson(bodyType, "body", 0))
+	}
+
+	method := DartMethod{Name: name, ReturnType: "void"}
+	if response := op.SuccessResponse(); response != nil {
+		responseType := m.resolve(owner, "Response", response.Type)
+		method.ReturnType = dartTypeString(responseType)
+		method.Result = dartFromJson(responseType, "json", 0)
+	}
+
+	named := append(required, optional...)
+	params := positional
+	if len(named) > 0 {
+		params = append(params, "{"+strings.Join(named, ", ")+"}")
+	}
+	method.Params = strings.Join(params, ", ")
+	// Long parameter lists are split one per line, as dart format does
+	if len(method.Params)+len(name)+len(method.ReturnType) > 60 {
+		switch {
+		case len(named) == 0:
+			method.Params = "\n    " + strings.Join(positional, ",\n    ") + ",\n  "
+		case len(positional) == 0:
+			method.Params = "{\n    " + strings.Join(named, ",\n    ") + ",\n  }"
+		default:
+			method.Params = "\n    " + strings.Join(positional, ",\n    ") + ", {\n    " + strings.Join(named, ",\n    ") + ",\n  }"
+		}
+	}
+	method.Args = strings.Join(args, ", ")
+	if len(method.Args) > 60 {
+		method.Args = "\n      " + strings.Join(args, ",\n      ") + ",\n    "
+	}
+
+	method.Doc = dartDoc(operationDocText(op), "  ")
+	if op.Deprecated {
+		method.Doc = strings.TrimLeft(method.Doc+"\n  @Deprecated('Deprecated in the API specification')", "\n")
+	}
+	return method
+}
+
+// Make a type nullable
+func dartNullable(t *dartType) *dartType {
+	if t.nullable {
+		return t
+	}
+	nullable := *t
+	nullable.nullable = true
+	return &nullable
+}
+
+// Render a Dart type
+func dartTypeString(t *dartType) string {
+	var name string
+	switch {
+	case t.alias != "":
+		name = t.alias
+	case t.kind == dartAny:
+		return "Object?"
+	case t.kind == dartList:
+		name = "List<" + dartTypeString(t.elem) + ">"
+	case t.kind == dartMap:
+		name = "Map<String, " + dartTypeString(t.elem) + ">"
+	default:
+		name = t.name
+	}
+	if t.nullable && t.kind != dartAny {
+		return name + "?"
+	}
+	return name
+}
+
+// Build the expression converting a decoded JSON value to a type
+func dartFromJson(t *dartType, expr string, depth int) string {
+	nullable := func(conversion string) string {
+		if t.nullable {
+			return expr + " == null ? null : " + conversion
+		}
+		return conversion
+	}
+	q := ""
+	if t.nullable {
+		q = "?"
+	}
+	switch t.kind {
+	case dartScalar:
+		return expr + " as " + t.name + q
+	case dartInt:
+		return "(" + expr + " as num" + q + ")" + q + ".toInt()"
+	case dartDouble:
+		return "(" + expr + " as num" + q + ")" + q + ".toDouble()"
+	case dartDateTime, dartDate:
+		return nullable("DateTime.parse(" + expr + " as String)")
+	case dartClass:
+		return nullable(t.name + ".fromJson(" + expr + " as Map<String, dynamic>)")
+	case dartEnum:
+		return nullable(t.name + ".fromJson(" + expr + ")")
+	case dartList:
+		e := fmt.Sprintf("e%d", depth)
+		list := "(" + expr + " as List<dynamic>" + q + ")"
+		if t.elem.kind == dartAny {
+			return list
+		}
+		return list + q + ".map((" + e + ") => " + dartFromJson(t.elem, e, depth+1) + ").toList()"
+	case dartMap:
+		k, e := fmt.Sprintf("k%d", depth), fmt.Sprintf("e%d", depth)
+		values := "(" + expr + " as Map<String, dynamic>" + q + ")"
+		if t.elem.kind == dartAny {
+			return values
+		}
+		return values + q + ".map((" + k + ", " + e + ") => MapEntry(" + k + ", " + dartFromJson(t.elem, e, depth+1) + "))"
+	}
+	return expr
+}
+
+// Build the expression converting a value of a type to JSON
+func dartToJson(t *dartType, expr string, depth int) string {
+	q := ""
+	if t.nullable {
+		q = "?"
+	}
+	switch t.kind {
+	case dartDateTime:
+		return expr + q + ".toIso8601String()"
+	case dartDate:
+		return expr + q + ".toIso8601String().substring(0, 10)"
+	case dartClass, dartEnum:
+		return expr + q + ".toJson()"
+	case dartList:
+		e := fmt.Sprintf("e%d", depth)
+		if item := dartToJson(t.elem, e, depth+1); item != e {
+			return expr + q + ".map((" + e + ") => " + item + ").toList()"
+		}
+	case dartMap:
+		k, e := fmt.Sprintf("k%d", depth), fmt.Sprintf("e%d", depth)
+		if value := dartToJson(t.elem, e, depth+1); value != e {
+			return expr + q + ".map((" + k + ", " + e + ") => MapEntry(" + k + ", " + value + "))"
+		}
+	}
+	return expr
+}
+
+func dartEnumBase(t *ir.Type) string {
+	switch t.Kind {
+	case ir.String:
+		return "String"
+	case ir.Integer:
+		return "int"
+	}
+	return ""
+}
+
+func dartEnumDecl(name string, t *ir.Type) DartEnum {
+	return DartEnum{
+		Name:    name,
+		Doc:     dartDoc(t.Description, ""),
+		Base:    dartEnumBase(t),
+		Members: dartEnumMembers(t),
+	}
+}
+
+// Get the members of a string or integer enum, named by x-enum-varnames or after their values
+func dartEnumMembers(t *ir.Type) []DartMember {
+	var members []DartMember
+	taken := make(map[string]bool)
+	for _, name := range dartReservedEnumMembers {
+		taken[name] = true
+	}
+	for i, value := range t.Enum {
+		var literal string
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			literal = dartString(v)
+		case float64:
+			if t.Kind == ir.Integer && v != float64(int64(v)) {
+				continue
+			}
+			literal = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			literal = fmt.Sprint(v)
+		}
+		if t.Kind == ir.String {
+			literal = dartString(fmt.Sprint(value))
+		}
+		label := fmt.Sprint(value)
+		if i < len(t.EnumNames) {
+			label = t.EnumNames[i]
+		}
+		name := uniqueName(dartMemberName(label), func(n string) bool { return taken[n] })
+		taken[name] = true
+		member := DartMember{Name: name, Value: literal, Separator: ","}
+		if i < len(t.EnumDescriptions) {
+			member.Doc = dartDoc(t.EnumDescriptions[i], "  ")
+		}
+		members = append(members, member)
+	}
+	if len(members) > 0 {
+		members[len(members)-1].Separator = ";"
+	}
+	return members
+}
+
+// Convert a name to a public Dart type name: leading underscores, which make names private to
+// their library, become $, and the first letter is upper case
+func dartTypeName(name string) string {
+	name = dartLeadingUnderscores.ReplaceAllString(name, "$")
+	if name == "" {
+		return "Model"
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	if unicode.IsDigit(runes[0]) {
+		return "$" + string(runes)
+	}
+	return string(runes)
+}
+
+// Convert a spec name to a lowerCamelCase Dart member name (pet_id -> petId)
+func dartMemberName(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return "value"
+	}
+	var b strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+	name := b.String()
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "v" + name
+	}
+	if dartKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// Get the Dart name of an operation or resource: its TypeScript name when that is a valid public
+// Dart name, so that both clients share naming
+func dartClientName(name string) string {
+	if dartIdentifierPattern.MatchString(name) && !dartKeywords[name] {
+		return name
+	}
+	return dartMemberName(name)
+}
+
+var dartIdentifierPattern = regexp.MustCompile(`^[a-zA-Z$][a-zA-Z0-9_$]*$`)
+
+// Convert a spec name to UpperCamelCase, for hoisted declaration names
+func dartTitle(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// Quote a string as a Dart single-quoted literal, escaping interpolation
+func dartString(s string) string {
+	return "'" + dartStringContent(s) + "'"
+}
+
+func dartStringContent(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', '\'', '$':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Build the Dart string for a request path, interpolating the encoded path parameters
+func dartPathExpression(p string, pathArgs map[string]string) string {
+	var b strings.Builder
+	rest := p
+	for {
+		start := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if start < 0 || end < start {
+			break
+		}
+		b.WriteString(dartStringContent(rest[:start]))
+		name := rest[start+1 : end]
+		if arg, ok := pathArgs[name]; ok {
+			b.WriteString("${pathParam(" + arg + ")}")
+		} else {
+			b.WriteString(dartStringContent(rest[start : end+1]))
+		}
+		rest = rest[end+1:]
+	}
+	b.WriteString(dartStringContent(rest))
+	return "'" + b.String() + "'"
+}
+
+// Build /// doc comment lines at an indentation, or ""
+func dartDoc(text, indent string) string {
+	lines := splitDocText(text)
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(indent+"/// "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Remove deprecated operations from the spec, returning how many were removed
+func excludeDeprecatedOperations(spec *OpenAPISpec) int {
+	return removeOperations(spec, func(_, _ string, op *Operation) bool {
+		return op.Deprecated
+	})
+}
+
+// Collect the deprecated items that end up in the generated client, sorted for stable output
+func collectDeprecations(api *ir.API) []string {
+	var items []string
+
+	// An operation listed by several resources is reported once
+	seen := make(map[*ir.Operation]bool)
+	for _, resource := range api.Resources {
+		for _, op := range resource.Operations {
+			if seen[op] {
+				continue
+			}
+			seen[op] = true
+			if op.Deprecated {
+				items = append(items, fmt.Sprintf("operation %s (%s %s)", op.ID, op.Method, op.Path))
+			}
+			for _, param := range op.Params {
+				if param.Deprecated {
+					items = append(items, fmt.Sprintf("parameter %s.%s (in %s)", op.ID, param.Name, param.In))
+				}
+			}
+		}
+	}
+
+	// Only declarations reachable from a resource are written to the types files
+	emitted := make(map[string]*ir.Decl)
+	for _, resource := range api.Resources {
+		for name, decl := range collectResourceDecls(api, resource) {
+			emitted[name] = decl
+		}
+	}
+	for name, decl := range emitted {
+		if decl.Type.Deprecated {
+			items = append(items, "schema "+name)
+		}
+		for _, f := range decl.Type.Fields {
+			if f.Type.Deprecated {
+				items = append(items, fmt.Sprintf("property %s.%s", name, f.Name))
+			}
+		}
+	}
+
+	sort.Strings(items)
+	return items
+}
+
+// Build a JSDoc comment marking a described item as deprecated
+func deprecatedDoc(description, indent string) string {
+	return docComment(append(splitDocText(description), "@deprecated"), indent)
+}
+
+// Build the JSDoc line for a deprecated path parameter, which has no declaration of its own to annotate
+func deprecatedParamLine(name, description string) string {
+	line := "@param " + name + " Deprecated."
+	if description := strings.TrimSpace(description); description != "" {
+		line += " " + strings.Join(strings.Fields(description), " ")
+	}
+	return escapeDocText(line)
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Enum output styles selected with -enum-style
+const (
+	// EnumStyleAuto emits a TypeScript enum when member names are given (x-enum-varnames,
+	// x-enumNames) and a literal union otherwise
+	EnumStyleAuto        = "auto"
+	EnumStyleUnion       = "union"
+	EnumStyleEnum        = "enum"
+	EnumStyleConstEnum   = "const-enum"
+	EnumStyleConstObject = "const-object"
+)
+
+// ValidateEnumStyle checks an -enum-style value
+func ValidateEnumStyle(style string) error {
+	switch style {
+	case "", EnumStyleAuto, EnumStyleUnion, EnumStyleEnum, EnumStyleConstEnum, EnumStyleConstObject:
+		return nil
+	}
+	return fmt.Errorf("unknown enum style %q (expected auto, union, enum, const-enum or const-object)", style)
+}
+
+// Get the named members of an enum, or nil when it is emitted as a literal union. Member names
+// come from x-enum-varnames or x-enumNames, or are derived from the values.
+func enumMembers(t *ir.Type, config Config) []EnumMember {
+	if len(t.Enum) == 0 || config.EnumStyle == EnumStyleUnion {
+		return nil
+	}
+
+	names := t.EnumNames
+	hasNames := len(names) == len(t.Enum)
+	if !hasNames && (config.EnumStyle == "" || config.EnumStyle == EnumStyleAuto) {
+		return nil
+	}
+
+	// TypeScript enums only hold strings and numbers; const objects take any literal
+	if config.EnumStyle != EnumStyleConstObject && !isEnumObjectCompatible(t.Enum) {
+		return nil
+	}
+
+	taken := make(map[string]bool)
+	members := make([]EnumMember, 0, len(t.Enum))
+	for i, val := range t.Enum {
+		name := enumMemberName(val)
+		if hasNames {
+			name = toIdentifier(names[i])
+		}
+		name = uniqueName(name, func(n string) bool { return taken[n] })
+		taken[name] = true
+
+		member := EnumMember{Name: name, Value: jsLiteral(val)}
+		if i < len(t.EnumDescriptions) {
+			member.Doc = docComment(splitDocText(t.EnumDescriptions[i]), "  ")
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// Report whether every value is a string or number, as TypeScript enums and z.nativeEnum require
+func isEnumObjectCompatible(values []interface{}) bool {
+	for _, val := range values {
+		switch val.(type) {
+		case string, int, int64, uint64, float64:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Derive an enum member name from its value (in-progress -> InProgress, 404 -> Value404)
+func enumMemberName(val interface{}) string {
+	text := fmt.Sprint(val)
+	if val == nil {
+		text = "null"
+	}
+	switch {
+	case text == "":
+		return "Empty"
+	case strings.HasPrefix(text, "-"):
+		text = "Minus" + text[1:]
+	case unicode.IsDigit([]rune(text)[0]):
+		text = "Value" + text
+	}
+	return toTitleCase(toIdentifier(text))
+}
+
+// Document the values of an enum emitted as a literal union with their x-enum-descriptions
+func enumDescriptionLines(t *ir.Type) []string {
+	var lines []string
+	for i, description := range t.EnumDescriptions {
+		if i >= len(t.Enum) || strings.TrimSpace(description) == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", jsLiteral(t.Enum[i]), strings.Join(strings.Fields(description), " ")))
+	}
+	return lines
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Escape text for use inside a JSDoc comment; a literal "*/" would terminate the comment early
+func escapeDocText(s string) string {
+	return strings.ReplaceAll(s, "*/", "*\\/")
+}
+
+// Split spec text into lines on any line terminator, trimming trailing whitespace and surrounding
+// blank lines
+func splitDocText(s string) []string {
+	s = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\u2028", "\n", "\u2029", "\n").Replace(s)
+	s = strings.Trim(s, "\n")
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return lines
+}
+
+// Render spec text for a single-line JSDoc tag, collapsing line breaks and runs of whitespace
+func docInline(s string) string {
+	return escapeDocText(strings.Join(strings.Fields(s), " "))
+}
+
+// Render spec text after a JSDoc tag, continuing extra lines with " * "
+func docMultiline(s string) string {
+	var b strings.Builder
+	for i, line := range splitDocText(s) {
+		if i > 0 {
+			b.WriteString("\n *")
+			if line != "" {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(escapeDocText(line))
+	}
+	return b.String()
+}
+
+// Render a string as a single-quoted JavaScript string literal
+func jsSingleQuoted(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\u2028':
+			b.WriteString(`\u2028`)
+		case '\u2029':
+			b.WriteString(`\u2029`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\x%02x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Code that a spec string could inject right after closing a comment early
+var injectedAfterComment = regexp.MustCompile(`\*/ *(export|process|console|alert)`)
+
+// Fail if any generated file lets a spec string end a comment or contains a raw </script>
+func assertNoInjection(t *testing.T, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if loc := injectedAfterComment.FindStringIndex(content); loc != nil {
+			t.Errorf("%s closes a comment before spec text: %q", name, content[loc[0]:loc[1]])
+		}
+		if strings.Contains(content, "</script>") {
+			t.Errorf("%s contains a raw </script>", name)
+		}
+	}
+}
+
+func TestHostileComments(t *testing.T) {
+	files := generateTypeScriptFiles(t, "testdata/hostile/comments.yaml")
+	assertNoInjection(t, files)
+
+	assertContains(t, files, "types/itemsexportconstpwned1.types.ts",
+		"/** An item *\\/ export const pwned = 1; /* */",
+		"   * description with *\\/ inside\n",
+		"   * @example \"*\\/ alert(1) /*\"\n")
+	assertContains(t, files, "resources/itemsexportconstpwned1/operations/list-items.ts",
+		"/** Filter *\\/ console.log('injected') /* */",
+		"   * Line one\n   * Line two\n   * Line three\n",
+		" * Closes the comment *\\/ process.exit(1); /* and keeps going.\n",
+		" * @tags items *\\/ export const pwned = 1; /*\n",
+		" * @summary Lists items *\\/ export const pwned = 1; /*\n")
+}
+
+func TestHostileLiterals(t *testing.T) {
+	files := generateTypeScriptFiles(t, "testdata/hostile/literals.yaml")
+	assertNoInjection(t, files)
+
+	quote := `["say \"hi\"", "it's", "back\\slash", "new\nline", "${process.exit(1)}", "\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"]`
+	assertContains(t, files, "types/settings.types.ts",
+		`export type Quote = "say \"hi\"" | "it's" | "back\\slash" | "new\nline" | "${process.exit(1)}" | "\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e";`,
+		`   * @default "'; alert(1); '"`)
+	assertContains(t, files, "schemas/settings.schemas.ts",
+		"export const QuoteSchema = z.enum("+quote+");")
+	assertContains(t, files, "validators/settings.validators.ts",
+		"v.oneOfValues("+quote+")")
+	assertContains(t, files, "config/constants.ts",
+		`DEFAULT_BASE_URL: 'https://api.example.com/it\'s\'; process.exit(1); \'',`)
+}
+
+func TestHostilePaths(t *testing.T) {
+	files := generateTypeScriptFiles(t, "testdata/hostile/paths.yaml")
+	assertNoInjection(t, files)
+
+	assertContains(t, files, "resources/files/operations/get-file-process-exit1.ts",
+		`"sort'order"?: string;`,
+		`const pathParams = { "file-id": fileId };`,
+		`replacePath('/files/it\'s/{file-id}', pathParams);`)
+	assertContains(t, files, "resources/rawpath/operations/post-raw-path$-x.ts",
+		"replacePath('/raw\\\\path/`${x}`', pathParams);")
+}
+
+func TestHostileTraversal(t *testing.T) {
+	_, err := GenerateTypeScript(Config{
+		InputPath:  "testdata/hostile/traversal.yaml",
+		OutputPath: t.TempDir(),
+		Language:   "typescript",
+		GroupBy:    GroupByTag,
+	})
+	if err == nil || !strings.Contains(err.Error(), `unsafe name "../../etc" at #/paths/~1users/get/tags/1`) {
+		t.Fatalf("expected an unsafe name error, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"math"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Example strings for the string formats, which a plain "string" would not satisfy
+var formatExamples = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"time":      "12:00:00",
+	"email":     "user@example.com",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "ZXhhbXBsZQ==",
+}
+
+// Build example data for a type: the schema's example or default when it has one, else a value of
+// the right shape within its constraints. Optional properties are filled in too, except where
+// they would recurse into a schema being built.
+func exampleValue(t *ir.Type, decls map[string]*ir.Decl) any {
+	return exampleFor(t, decls, make(map[string]bool))
+}
+
+func exampleFor(t *ir.Type, decls map[string]*ir.Decl, visiting map[string]bool) any {
+	if t == nil {
+		return nil
+	}
+	if t.Example != nil {
+		return t.Example
+	}
+	if t.Default != nil {
+		return t.Default
+	}
+	if t.Kind == ir.Ref {
+		decl := decls[t.Ref]
+		if decl == nil || visiting[t.Ref] {
+			return nil
+		}
+		visiting[t.Ref] = true
+		defer delete(visiting, t.Ref)
+		return exampleFor(decl.Type, decls, visiting)
+	}
+	for _, value := range t.Enum {
+		if value != nil {
+			return value
+		}
+	}
+
+	switch t.Kind {
+	case ir.String:
+		return exampleString(t)
+	case ir.Integer:
+		return int64(exampleNumber(t, true))
+	case ir.Number:
+		return exampleNumber(t, false)
+	case ir.Boolean:
+		return true
+	case ir.Array:
+		item := exampleFor(t.Items, decls, visiting)
+		count := 1
+		if t.MinItems != nil && *t.MinItems > count {
+			count = *t.MinItems
+		}
+		if t.MaxItems != nil && *t.MaxItems < count {
+			count = *t.MaxItems
+		}
+		items := []any{}
+		if item == nil && (t.Items == nil || !t.Items.Nullable) {
+			return items
+		}
+		for i := 0; i < count; i++ {
+			items = append(items, item)
+		}
+		return items
+	case ir.Map:
+		return map[string]any{}
+	case ir.Object:
+		return exampleObject(t.Fields, decls, visiting)
+	case ir.Intersection:
+		merged := map[string]any{}
+		for _, variant := range t.Variants {
+			if object, ok := exampleFor(variant, decls, visiting).(map[string]any); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	case ir.Union:
+		for _, variant := range t.Variants {
+			if value := exampleFor(variant, decls, visiting); value != nil {
+				return value
+			}
+		}
+	}
+	return nil
+}
+
+func exampleObject(fields []*ir.Field, decls map[string]*ir.Decl, visiting map[string]bool) map[string]any {
+	object := map[string]any{}
+	for _, f := range fields {
+		value := exampleFor(f.Type, decls, visiting)
+		if value == nil && !f.Required {
+			continue
+		}
+		object[f.Name] = value
+	}
+	return object
+}
+
+func exampleString(t *ir.Type) string {
+	value, ok := formatExamples[t.Format]
+	if !ok {
+		value = "string"
+	}
+	if t.MinLength != nil && len(value) < *t.MinLength {
+		value += strings.Repeat("x", *t.MinLength-len(value))
+	}
+	if t.MaxLength != nil && len(value) > *t.MaxLength {
+		value = value[:*t.MaxLength]
+	}
+	return value
+}
+
+// Get 0, moved into the minimum and maximum
+func exampleNumber(t *ir.Type, integer bool) float64 {
+	value := 0.0
+	if t.Minimum != nil && value < *t.Minimum {
+		value = *t.Minimum
+	}
+	if t.Maximum != nil && value > *t.Maximum {
+		value = *t.Maximum
+	}
+	if integer {
+		value = math.Ceil(value)
+		if t.Maximum != nil && value > *t.Maximum {
+			value = math.Floor(*t.Maximum)
+		}
+	}
+	return value
+}
+
+// Get the example a response is answered with: the named example when there is one by that name,
+// else the response's example, else its first named example, else one built from its schema
+func responseExample(response *ir.Response, name string, decls map[string]*ir.Decl) any {
+	if example, ok := response.Examples[name]; ok && name != "" {
+		return example
+	}
+	if response.Example != nil {
+		return response.Example
+	}
+	if names := sortedKeys(response.Examples); len(names) > 0 {
+		return response.Examples[names[0]]
+	}
+	return exampleValue(response.Type, decls)
+}
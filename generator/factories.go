@@ -0,0 +1,381 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for the factories of the component schemas
+type FactoriesTemplateData struct {
+	Factories []FactoryDef
+}
+
+type FactoryDef struct {
+	Name string
+	Type string
+	// Overrides is set for object schemas, whose factories take a Partial of the type
+	Overrides bool
+	Body      string
+}
+
+// Generates the build expressions of the factories. Owner is the schema being built, whose fields
+// leading back to it are built to a limited depth so that recursive schemas stay finite.
+type factoryModel struct {
+	config  Config
+	decls   map[string]*ir.Decl
+	reaches map[string]map[string]bool
+	// names are the factory names of the schemas
+	names map[string]string
+	owner string
+}
+
+// Generate the -factories data builders in factories/, one build<Schema> per declared type
+func generateFactories(api *ir.API, config Config) error {
+	factoriesPath := filepath.Join(config.OutputPath, "factories")
+	if err := os.MkdirAll(factoriesPath, 0755); err != nil {
+		return err
+	}
+
+	// Only the declarations written to the types files have a type to build; they include every
+	// declaration they refer to
+	owners := schemaOwners(api)
+	var decls []*ir.Decl
+	for _, decl := range api.Types {
+		if _, ok := owners[decl.Name]; ok {
+			decls = append(decls, decl)
+		}
+	}
+
+	m := &factoryModel{config: config, decls: declsByName(api), reaches: declReaches(api), names: make(map[string]string)}
+	taken := make(map[string]bool)
+	for _, decl := range decls {
+		name := uniqueName("build"+strings.ToUpper(decl.Name[:1])+decl.Name[1:], func(n string) bool { return taken[n] })
+		taken[name] = true
+		m.names[decl.Name] = name
+	}
+
+	var data FactoriesTemplateData
+	for _, decl := range decls {
+		m.owner = decl.Name
+		data.Factories = append(data.Factories, m.factory(decl))
+	}
+
+	for name, fileName := range map[string]string{
+		"factories/runtime.tmpl": "runtime.ts",
+		"factories/index.tmpl":   "index.ts",
+	} {
+		tmpl, err := loadTemplate(name)
+		if err != nil {
+			return err
+		}
+		content, err := executeTemplate(tmpl, data)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(factoriesPath, fileName), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find the schemas each schema refers to, directly or through others
+func declReaches(api *ir.API) map[string]map[string]bool {
+	direct := make(map[string][]string)
+	for _, decl := range api.Types {
+		ir.Walk(decl.Type, func(t *ir.Type) {
+			if t.Kind == ir.Ref {
+				direct[decl.Name] = append(direct[decl.Name], t.Ref)
+			}
+		})
+	}
+
+	reaches := make(map[string]map[string]bool)
+	for _, decl := range api.Types {
+		seen := make(map[string]bool)
+		pending := append([]string{}, direct[decl.Name]...)
+		for len(pending) > 0 {
+			name := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			pending = append(pending, direct[name]...)
+		}
+		reaches[decl.Name] = seen
+	}
+	return reaches
+}
+
+func (m *factoryModel) factory(decl *ir.Decl) FactoryDef {
+	t := decl.Type
+	def := FactoryDef{
+		Name: m.names[decl.Name],
+		Type: "Types." + decl.Name,
+	}
+
+	switch resolveRef(t, m.decls).Kind {
+	case ir.Object, ir.Map, ir.Intersection:
+		def.Overrides = !m.isMapped(t) && len(t.Enum) == 0
+	}
+	if !def.Overrides {
+		def.Body = m.expr(t, decl.Name, "")
+		if len(t.Enum) > 0 && !m.isMapped(t) && def.Body != "null" {
+			// The declared type may be a TypeScript enum rather than the union of its values
+			def.Body = strings.TrimSuffix(def.Body, " as "+tsBaseType(t, m.config, false)) + " as " + def.Type
+		}
+		return def
+	}
+
+	var parts []string
+	switch t.Kind {
+	case ir.Ref:
+		def.Body = fmt.Sprintf("%s(overrides)", m.names[t.Ref])
+		return def
+	case ir.Intersection:
+		parts = m.spreads(t, "  ")
+	default:
+		parts = m.fields(t, "  ")
+	}
+	parts = append(parts, "  ...overrides,")
+	def.Body = "({\n" + strings.Join(parts, "\n") + "\n})"
+	return def
+}
+
+// Get the members of an object literal for an intersection: a spread of each object variant
+func (m *factoryModel) spreads(t *ir.Type, indent string) []string {
+	var parts []string
+	for _, variant := range t.Variants {
+		switch resolveRef(variant, m.decls).Kind {
+		case ir.Object, ir.Map, ir.Intersection:
+		default:
+			continue
+		}
+		switch variant.Kind {
+		case ir.Object, ir.Map:
+			parts = append(parts, m.fields(variant, indent)...)
+		case ir.Intersection:
+			parts = append(parts, m.spreads(variant, indent)...)
+		default:
+			parts = append(parts, fmt.Sprintf("%s...%s,", indent, m.expr(variant, "", indent)))
+		}
+	}
+	return parts
+}
+
+// Get the members of an object literal for an object's fields
+func (m *factoryModel) fields(t *ir.Type, indent string) []string {
+	names := fieldNames(t.Fields, m.config)
+	var parts []string
+	for _, f := range t.Fields {
+		value := m.fieldExpr(f, indent)
+		parts = append(parts, fmt.Sprintf("%s%s: %s,", indent, propertyKey(names[f.Name]), value))
+	}
+	return parts
+}
+
+// Get the value of a field. Fields leading back to the schema being built are only built to a
+// limited depth, then left undefined, null or empty when their type allows it.
+func (m *factoryModel) fieldExpr(f *ir.Field, indent string) string {
+	value := m.expr(f.Type, f.Name, indent)
+	if !m.recursive(f.Type) {
+		return value
+	}
+	if strings.HasPrefix(value, "{") {
+		value = "(" + value + ")"
+	}
+	nested := fmt.Sprintf("r.nested(() => %s)", value)
+	switch {
+	case !f.Required:
+		return nested
+	case f.Type.Nullable:
+		return nested + " ?? null"
+	case f.Type.Kind == ir.Array && (f.Type.MinItems == nil || *f.Type.MinItems == 0):
+		return nested + " ?? []"
+	}
+	return value
+}
+
+// Report whether a type leads back to the schema being built
+func (m *factoryModel) recursive(t *ir.Type) bool {
+	found := false
+	ir.Walk(t, func(t *ir.Type) {
+		if t.Kind == ir.Ref && (t.Ref == m.owner || m.reaches[t.Ref][m.owner]) {
+			found = true
+		}
+	})
+	return found
+}
+
+func (m *factoryModel) isMapped(t *ir.Type) bool {
+	return t.Kind != ir.Ref && typeMapping(t.Override, t.Format, m.config) != ""
+}
+
+// Build the expression generating a value of a type. Name names the value in generated strings.
+func (m *factoryModel) expr(t *ir.Type, name, indent string) string {
+	if t == nil {
+		return "null"
+	}
+	if t.Kind == ir.Ref {
+		if name, ok := m.names[t.Ref]; ok {
+			return name + "()"
+		}
+		return "null"
+	}
+	if mapped := typeMapping(t.Override, t.Format, m.config); mapped != "" {
+		return m.mappedExpr(t, mapped, name)
+	}
+	return m.valueExpr(t, name, indent)
+}
+
+// Build the expression generating a value of a type, ignoring a mapping of its format
+func (m *factoryModel) valueExpr(t *ir.Type, name, indent string) string {
+	if len(t.Enum) > 0 {
+		var values []string
+		for _, value := range t.Enum {
+			if value != nil {
+				values = append(values, jsLiteral(value))
+			}
+		}
+		if len(values) == 0 {
+			return "null"
+		}
+		return fmt.Sprintf("r.pick([%s]) as %s", strings.Join(values, ", "), tsBaseType(t, m.config, false))
+	}
+
+	switch t.Kind {
+	case ir.String:
+		return m.stringExpr(t, name)
+	case ir.Integer:
+		lo, hi := numberRange(t, true)
+		return fmt.Sprintf("r.int(%s, %s)", jsNumber(lo), jsNumber(hi))
+	case ir.Number:
+		lo, hi := numberRange(t, false)
+		return fmt.Sprintf("r.number(%s, %s)", jsNumber(lo), jsNumber(hi))
+	case ir.Boolean:
+		return "r.bool()"
+	case ir.Array:
+		lo, hi := 1, 3
+		if t.MinItems != nil {
+			lo, hi = *t.MinItems, *t.MinItems+2
+		}
+		if t.MaxItems != nil && *t.MaxItems < hi {
+			hi = *t.MaxItems
+			lo = min(lo, hi)
+		}
+		item := m.expr(t.Items, name, indent)
+		if strings.HasPrefix(item, "{") {
+			// An object literal, not a block
+			item = "(" + item + ")"
+		}
+		return fmt.Sprintf("r.times(%d, %d, () => %s)", lo, hi, item)
+	case ir.Map:
+		if len(t.Fields) == 0 {
+			return "{}"
+		}
+		return m.objectLiteral(m.fields(t, indent+"  "), indent)
+	case ir.Object:
+		return m.objectLiteral(m.fields(t, indent+"  "), indent)
+	case ir.Intersection:
+		return m.objectLiteral(m.spreads(t, indent+"  "), indent)
+	case ir.Union:
+		// The first variant that does not lead back to the schema being built
+		for _, variant := range t.Variants {
+			if !m.recursive(variant) {
+				return m.expr(variant, name, indent)
+			}
+		}
+		if len(t.Variants) > 0 {
+			return m.expr(t.Variants[0], name, indent)
+		}
+	}
+	return "null"
+}
+
+func (m *factoryModel) objectLiteral(parts []string, indent string) string {
+	if len(parts) == 0 {
+		return "{}"
+	}
+	return "{\n" + strings.Join(parts, "\n") + "\n" + indent + "}"
+}
+
+func (m *factoryModel) stringExpr(t *ir.Type, name string) string {
+	var value string
+	if _, ok := formatExamples[t.Format]; ok {
+		value = fmt.Sprintf("r.format(%s)", jsSingleQuoted(t.Format))
+	} else {
+		value = fmt.Sprintf("r.string(%s)", jsSingleQuoted(name))
+	}
+	if t.MinLength == nil && t.MaxLength == nil {
+		return value
+	}
+	bound := func(n *int) string {
+		if n == nil {
+			return "undefined"
+		}
+		return strconv.Itoa(*n)
+	}
+	return fmt.Sprintf("r.fit(%s, %s, %s)", value, bound(t.MinLength), bound(t.MaxLength))
+}
+
+// Build a value of a type mapped with -type-map or x-sveger-type
+func (m *factoryModel) mappedExpr(t *ir.Type, mapped, name string) string {
+	switch mapped {
+	case "Date":
+		format := "date-time"
+		if t.Format == "date" {
+			format = "date"
+		}
+		return fmt.Sprintf("new Date(r.format('%s'))", format)
+	case "bigint":
+		lo, hi := numberRange(t, true)
+		return fmt.Sprintf("BigInt(r.int(%s, %s))", jsNumber(lo), jsNumber(hi))
+	case "Blob":
+		return fmt.Sprintf("new Blob([r.string(%s)])", jsSingleQuoted(name))
+	case "File":
+		return fmt.Sprintf("new File([r.string(%s)], %s)", jsSingleQuoted(name), jsSingleQuoted(name+".txt"))
+	case "ArrayBuffer":
+		return "new ArrayBuffer(8)"
+	case "Uint8Array":
+		return "new Uint8Array(8)"
+	}
+
+	value := m.valueExpr(t, name, "")
+	switch mapped {
+	case "string", "number", "boolean":
+		return value
+	}
+	return fmt.Sprintf("(%s as unknown as %s)", value, qualifyMappedType(mapped, false))
+}
+
+// Get the range numbers are picked from: the schema's minimum and maximum, else 1 to 1000
+func numberRange(t *ir.Type, integer bool) (float64, float64) {
+	lo, hi := 1.0, 1000.0
+	if t.Minimum != nil {
+		lo, hi = *t.Minimum, *t.Minimum+999
+	}
+	if t.Maximum != nil {
+		hi = *t.Maximum
+		if lo > hi {
+			lo = hi - 999
+		}
+	}
+	if integer {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+		if lo > hi {
+			lo = hi
+		}
+	}
+	return lo, hi
+}
+
+func jsNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,222 @@
+package generator
+
+import (
+	"path"
+	"strings"
+)
+
+// Remove the operations rejected by the configured filters or marked x-sveger-ignore, returning how
+// many were removed
+func filterOperations(spec *OpenAPISpec, config Config) int {
+	return removeOperations(spec, func(p, method string, op *Operation) bool {
+		return !keepOperation(p, method, op, config)
+	})
+}
+
+// Remove every operation for which drop returns true, returning how many were removed
+func removeOperations(spec *OpenAPISpec, drop func(p, method string, op *Operation) bool) int {
+	removed := 0
+	for p, pathItem := range spec.Paths {
+		for _, method := range []struct {
+			name string
+			op   **Operation
+		}{
+			{"GET", &pathItem.Get},
+			{"POST", &pathItem.Post},
+			{"PUT", &pathItem.Put},
+			{"DELETE", &pathItem.Delete},
+			{"PATCH", &pathItem.Patch},
+		} {
+			if *method.op != nil && drop(p, method.name, *method.op) {
+				*method.op = nil
+				removed++
+			}
+		}
+		spec.Paths[p] = pathItem
+	}
+	return removed
+}
+
+// Decide whether an operation is generated. An operation is kept when it matches any include filter
+// (or none are set) and no exclude filter.
+func keepOperation(p, method string, op *Operation, config Config) bool {
+	if op.XSvegerIgnore {
+		return false
+	}
+	for _, tag := range op.Tags {
+		if containsFold(config.ExcludeTags, tag) {
+			return false
+		}
+	}
+
+	if len(config.IncludeTags) == 0 && len(config.IncludePaths) == 0 && len(config.IncludeOps) == 0 {
+		return true
+	}
+	for _, tag := range op.Tags {
+		if containsFold(config.IncludeTags, tag) {
+			return true
+		}
+	}
+	for _, pattern := range config.IncludePaths {
+		if matchPathGlob(pattern, p) {
+			return true
+		}
+	}
+	operationID := op.OperationID
+	if operationID == "" {
+		operationID = generateOperationID(method, p)
+	}
+	return contains(config.IncludeOps, operationID)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match a URL path against a glob where * matches within a segment and ** matches any number of
+// segments (e.g. /api/v1/users/**)
+func matchPathGlob(pattern, p string) bool {
+	return matchSegments(splitPath(pattern), splitPath(p))
+}
+
+func splitPath(p string) []string {
+	trimmed := strings.Trim(p, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], segments[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// Drop properties marked x-sveger-ignore from every component schema
+func removeIgnoredProperties(spec *OpenAPISpec) {
+	for _, schema := range getAllSchemas(spec) {
+		walkSchema(schema, func(s *Schema) {
+			for name, prop := range s.Properties {
+				if prop != nil && prop.XSvegerIgnore {
+					delete(s.Properties, name)
+					s.Required = without(s.Required, name)
+				}
+			}
+		})
+	}
+}
+
+func without(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Remove component schemas that are not reachable from any remaining operation, returning how many
+// were removed
+func pruneUnreachableSchemas(spec *OpenAPISpec) int {
+	allSchemas := make(map[string]*Schema)
+	for name, schema := range getAllSchemas(spec) {
+		allSchemas[sanitizeTypeName(name)] = schema
+	}
+
+	reachable := make(map[string]bool)
+	var visit func(schema *Schema)
+	visit = func(schema *Schema) {
+		for _, ref := range directRefs(schema) {
+			if !reachable[ref] {
+				reachable[ref] = true
+				visit(allSchemas[ref])
+			}
+		}
+	}
+	for _, pathItem := range spec.Paths {
+		for _, method := range pathItem.operations() {
+			for _, param := range method.op.Parameters {
+				visit(param.Schema)
+			}
+			if method.op.RequestBody != nil {
+				for _, content := range method.op.RequestBody.Content {
+					visit(content.Schema)
+				}
+			}
+			for _, response := range method.op.Responses {
+				for _, content := range response.Content {
+					visit(content.Schema)
+				}
+				visit(response.Schema)
+			}
+		}
+	}
+
+	removed := 0
+	for _, schemas := range []map[string]*Schema{spec.Components.Schemas, spec.Definitions} {
+		for name := range schemas {
+			if !reachable[sanitizeTypeName(name)] {
+				delete(schemas, name)
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
+// Visit a schema and its inline subschemas, without following references
+func walkSchema(schema *Schema, visit func(*Schema)) {
+	if schema == nil {
+		return
+	}
+	visit(schema)
+	if schema.Ref != "" {
+		return
+	}
+	for _, prop := range schema.Properties {
+		walkSchema(prop, visit)
+	}
+	walkSchema(schema.Items, visit)
+	for _, sub := range schema.AllOf {
+		walkSchema(sub, visit)
+	}
+	for _, sub := range schema.OneOf {
+		walkSchema(sub, visit)
+	}
+	for _, sub := range schema.AnyOf {
+		walkSchema(sub, visit)
+	}
+}
+
+// Collect the component names a schema references directly, without following the references
+func directRefs(schema *Schema) []string {
+	seen := make(map[string]bool)
+	walkSchema(schema, func(s *Schema) {
+		if s.Ref != "" {
+			seen[getRefName(s.Ref)] = true
+		}
+	})
+	return sortedKeys(seen)
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Templates and test specs are found relative to the repository root
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// Generate a TypeScript client from a spec with zod schemas and response validators, and return
+// the generated files by path relative to the output directory
+func generateTypeScriptFiles(t *testing.T, inputPath string) map[string]string {
+	t.Helper()
+	config := Config{
+		InputPath:         inputPath,
+		Language:          "typescript",
+		Timeout:           "10000",
+		Zod:               true,
+		ValidateResponses: true,
+		GroupBy:           GroupByTag,
+		PropertyNaming:    PropertyNamingOriginal,
+		EnumStyle:         EnumStyleAuto,
+	}
+	return generateFiles(t, config, GenerateTypeScript)
+}
+
+// Run a generator into a temporary directory and return the generated files by path relative to it
+func generateFiles(t *testing.T, config Config, generate func(Config) (*Report, error)) map[string]string {
+	t.Helper()
+	outputPath := t.TempDir()
+	config.OutputPath = outputPath
+	if _, err := generate(config); err != nil {
+		t.Fatalf("generate %s: %v", config.InputPath, err)
+	}
+
+	files := make(map[string]string)
+	err := filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(outputPath, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// Fail unless the generated file exists and contains every snippet
+func assertContains(t *testing.T, files map[string]string, name string, snippets ...string) {
+	t.Helper()
+	content, ok := files[name]
+	if !ok {
+		t.Fatalf("%s was not generated", name)
+	}
+	for _, snippet := range snippets {
+		if !strings.Contains(content, snippet) {
+			t.Errorf("%s does not contain %q:\n%s", name, snippet, content)
+		}
+	}
+}
@@ -0,0 +1,761 @@
+package generator
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for the Go client
+type GoClientTemplateData struct {
+	Package        string
+	DefaultBaseURL string
+	Services       []GoServiceDef
+}
+
+type GoTypesTemplateData struct {
+	Package string
+	Imports []string
+	Decls   []GoDecl
+}
+
+type GoServiceTemplateData struct {
+	Package string
+	Imports []string
+	Service GoServiceDef
+	Params  []GoDecl
+	Methods []string
+}
+
+type GoServiceDef struct {
+	// Name is the service type, Field the Client field exposing it
+	Name     string
+	Field    string
+	Resource string
+}
+
+// GoDecl is a named Go type: a struct (Fields, Embeds), an enum (Consts) or a defined type (Type)
+type GoDecl struct {
+	Doc    string
+	Name   string
+	Type   string
+	Alias  bool
+	Struct bool
+	Embeds []string
+	Fields []GoField
+	Consts []GoConst
+}
+
+type GoField struct {
+	Doc  string
+	Name string
+	Type string
+	Tag  string
+}
+
+type GoConst struct {
+	Doc   string
+	Name  string
+	Value string
+}
+
+// goModel maps the API's types to Go types
+type goModel struct {
+	config Config
+	// names maps declaration names to Go type names
+	names map[string]string
+	// kinds records whether a named type is a struct, which fields and results point to
+	structs map[string]bool
+	decls   map[string]GoDecl
+	taken   map[string]bool
+}
+
+// Go reserved words and the locals used in generated methods, which parameters must not shadow
+var goReservedNames = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true,
+	"goto": true, "if": true, "import": true, "interface": true, "map": true, "package": true,
+	"range": true, "return": true, "select": true, "struct": true, "switch": true, "type": true,
+	"var": true, "ctx": true, "params": true, "body": true, "s": true, "path": true, "query": true,
+	"header": true, "out": true, "err": true, "url": true, "http": true, "fmt": true, "time": true,
+}
+
+var goInitialisms = map[string]string{
+	"api": "API", "html": "HTML", "http": "HTTP", "https": "HTTPS", "id": "ID", "ip": "IP",
+	"json": "JSON", "sql": "SQL", "uri": "URI", "url": "URL", "uuid": "UUID", "xml": "XML",
+}
+
+// GenerateGo generates a Go client package from the spec
+func GenerateGo(config Config) (*Report, error) {
+	api, report, err := prepareSpec(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(config.OutputPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := generateGoClient(api, config); err != nil {
+		return nil, err
+	}
+
+	report.Deprecated = collectDeprecations(api)
+	return report, nil
+}
+
+// Names declared by client.go that generated types must not take
+var goClientNames = []string{
+	"Client", "Option", "APIError", "HTTPDoer", "RequestEditor", "NewClient", "DefaultBaseURL",
+	"WithHTTPClient", "WithRequestEditor", "WithBearerToken",
+}
+
+func generateGoClient(api *ir.API, config Config) error {
+	pkg := goPackageName(config)
+	model := newGoModel(api, config, goClientNames)
+
+	serviceTmpl, err := loadTemplate("go/service.tmpl")
+	if err != nil {
+		return err
+	}
+
+	var services []GoServiceDef
+	fields := goResourceNames(api)
+	for i, resource := range api.Resources {
+		field := fields[i]
+		serviceName := model.reserve(field + "Service")
+
+		service := GoServiceDef{
+			Name:     serviceName,
+			Field:    field,
+			Resource: resource.Name,
+		}
+		services = append(services, service)
+
+		data := GoServiceTemplateData{Package: pkg, Service: service}
+		for j, methodName := range goMethodNames(resource) {
+			code, params := model.operation(service.Name, methodName, resource.Operations[j])
+			data.Methods = append(data.Methods, code)
+			if params != nil {
+				data.Params = append(data.Params, *params)
+			}
+		}
+		data.Imports = goImports(strings.Join(data.Methods, "\n")+goDeclsText(data.Params), "context", "net/http")
+
+		filePath, err := outputPath(config.OutputPath, strings.ToLower(field)+"_service.go")
+		if err != nil {
+			return err
+		}
+		if err := writeGoFile(serviceTmpl, data, filePath); err != nil {
+			return err
+		}
+	}
+
+	// Types are collected while generating the services, which can hoist inline schemas
+	if err := writeGoTypes(model, api, pkg, config.OutputPath); err != nil {
+		return err
+	}
+
+	clientTmpl, err := loadTemplate("go/client.tmpl")
+	if err != nil {
+		return err
+	}
+	return writeGoFile(clientTmpl, GoClientTemplateData{
+		Package:        pkg,
+		DefaultBaseURL: strconv.Quote(defaultBaseURL(api, config)),
+		Services:       services,
+	}, filepath.Join(config.OutputPath, "client.go"))
+}
+
+// Get the Go names of the resources, in the order of api.Resources. Resources differing only in
+// punctuation (user_accounts, userAccounts) share a Go name; the lower cased name also names the
+// resource's file, so names are unique regardless of case.
+func goResourceNames(api *ir.API) []string {
+	taken := make(map[string]bool)
+	names := make([]string, 0, len(api.Resources))
+	for _, resource := range api.Resources {
+		name := uniqueName(goName(resource.Name), func(n string) bool { return taken[strings.ToLower(n)] })
+		taken[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// Get the Go method names of a resource's operations, in order. Distinct operationIds can still
+// map to the same Go name (get_user_id, getUserId).
+func goMethodNames(resource *ir.Resource) []string {
+	taken := make(map[string]bool)
+	names := make([]string, 0, len(resource.Operations))
+	for _, op := range resource.Operations {
+		name := uniqueName(goName(op.ID), func(n string) bool { return taken[n] })
+		taken[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// Declare the API's types and write them, with the types hoisted so far, to types.go
+func writeGoTypes(model *goModel, api *ir.API, pkg, dir string) error {
+	for _, decl := range api.Types {
+		model.declare(decl)
+	}
+	typesTmpl, err := loadTemplate("go/types.tmpl")
+	if err != nil {
+		return err
+	}
+	typesData := GoTypesTemplateData{Package: pkg}
+	for _, name := range sortedKeys(model.decls) {
+		typesData.Decls = append(typesData.Decls, model.decls[name])
+	}
+	typesData.Imports = goImports(goDeclsText(typesData.Decls))
+	return writeGoFile(typesTmpl, typesData, filepath.Join(dir, "types.go"))
+}
+
+// Render a Go template and gofmt the result, failing on generated code that does not parse
+func writeGoFile(tmpl *template.Template, data any, path string) error {
+	content, err := executeTemplate(tmpl, data)
+	if err != nil {
+		return err
+	}
+	formatted, err := format.Source([]byte(content))
+	if err != nil {
+		return fmt.Errorf("generated invalid Go code for %s: %w", path, err)
+	}
+	return writeFile(path, string(formatted))
+}
+
+// Name the Go package after the output directory unless -go-package is set
+func goPackageName(config Config) string {
+	name := config.GoPackage
+	if name == "" {
+		abs, err := filepath.Abs(config.OutputPath)
+		if err != nil {
+			abs = config.OutputPath
+		}
+		name = filepath.Base(abs)
+	}
+	name = strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name))
+	if name == "" || unicode.IsDigit([]rune(name)[0]) || goReservedNames[name] {
+		return "client"
+	}
+	return name
+}
+
+// Create the model of an API. Reserved names are declared by the package's fixed files.
+func newGoModel(api *ir.API, config Config, reserved []string) *goModel {
+	m := &goModel{
+		config:  config,
+		names:   make(map[string]string),
+		structs: make(map[string]bool),
+		decls:   make(map[string]GoDecl),
+		taken:   make(map[string]bool),
+	}
+	for _, name := range reserved {
+		m.taken[name] = true
+	}
+
+	// Resolve every type's Go name up front so references render before their declarations
+	for _, decl := range api.Types {
+		goTypeName := m.reserve(goName(decl.Name))
+		m.names[decl.Name] = goTypeName
+		if isGoStruct(decl.Type) {
+			m.structs[goTypeName] = true
+		}
+	}
+	return m
+}
+
+// Take a package-level name, suffixed until it is unique
+func (m *goModel) reserve(name string) string {
+	name = uniqueName(name, func(n string) bool { return m.taken[n] })
+	m.taken[name] = true
+	return name
+}
+
+// Report whether a named type is declared as a Go struct
+func isGoStruct(t *ir.Type) bool {
+	if len(t.Enum) > 0 {
+		return false
+	}
+	return t.Kind == ir.Object ||
+		(t.Kind == ir.Intersection && (len(t.Variants) > 1 || t.Variants[0].Kind != ir.Ref))
+}
+
+// Declare a named type as a Go type
+func (m *goModel) declare(typeDecl *ir.Decl) {
+	name := m.names[typeDecl.Name]
+	t := typeDecl.Type
+	decl := GoDecl{Name: name, Doc: goDoc(name, t.Description, t.Deprecated)}
+
+	switch {
+	case len(t.Enum) > 0:
+		decl.Type = m.goScalarType(t)
+		taken := make(map[string]bool)
+		for i, val := range t.Enum {
+			memberName := goName(enumMemberName(val))
+			if len(t.EnumNames) == len(t.Enum) {
+				memberName = trimTypePrefix(goName(t.EnumNames[i]), name)
+			}
+			constName := uniqueName(name+memberName, func(n string) bool { return taken[n] || m.taken[n] })
+			taken[constName] = true
+			value := strconv.Quote(fmt.Sprint(val))
+			if decl.Type != "string" {
+				value = fmt.Sprint(val)
+			}
+			constDef := GoConst{Name: constName, Value: value}
+			if i < len(t.EnumDescriptions) {
+				constDef.Doc = goDoc("", t.EnumDescriptions[i], false)
+			}
+			decl.Consts = append(decl.Consts, constDef)
+		}
+	case isGoStruct(t):
+		decl.Struct = true
+		m.structFields(&decl, t)
+	case t.Kind == ir.Intersection:
+		decl.Type = m.goType(name, "", t.Variants[0])
+	case t.Kind == ir.Union:
+		// Unions are kept as raw JSON for the caller to decode
+		decl.Type = "json.RawMessage"
+		decl.Alias = true
+	default:
+		decl.Type = m.goType(name, "", t)
+	}
+	m.decls[name] = decl
+}
+
+// Add an object's fields to a struct, embedding the structs an intersection composes
+func (m *goModel) structFields(decl *GoDecl, t *ir.Type) {
+	for _, variant := range t.Variants {
+		if variant.Kind == ir.Ref {
+			// Only structs are flattened into JSON when embedded
+			if refName := m.refName(variant.Ref); m.structs[refName] {
+				decl.Embeds = append(decl.Embeds, refName)
+			}
+		} else {
+			m.structFields(decl, variant)
+		}
+	}
+
+	taken := make(map[string]bool)
+	for _, field := range decl.Fields {
+		taken[field.Name] = true
+	}
+	wireNames := make([]string, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		wireNames = append(wireNames, field.Name)
+	}
+	names := memberNames(wireNames, goName, taken)
+	for _, field := range t.Fields {
+		optional := !field.Required
+		fieldName := names[field.Name]
+
+		fieldType := m.goType(decl.Name, fieldName, field.Type)
+		if (optional || field.Type.Nullable) && m.pointable(fieldType) || fieldType == decl.Name {
+			fieldType = "*" + fieldType
+		}
+		tag := field.Name
+		if optional {
+			tag += ",omitempty"
+		}
+		tag = "json:" + strconv.Quote(tag)
+		decl.Fields = append(decl.Fields, GoField{
+			Doc:  goDoc("", field.Type.Description, field.Type.Deprecated),
+			Name: fieldName,
+			Type: fieldType,
+			Tag:  goTag(tag),
+		})
+	}
+}
+
+// Remove the end of a type name repeated at the start of an enum member name, so the member
+// CompanyStatusActive of ConstantsCompanyStatus becomes ConstantsCompanyStatusActive rather than
+// ConstantsCompanyStatusCompanyStatusActive
+func trimTypePrefix(memberName, typeName string) string {
+	words := splitWords(typeName)
+	for i := range words {
+		suffix := strings.Join(words[i:], "")
+		if rest := strings.TrimPrefix(memberName, suffix); rest != memberName && rest != "" {
+			return rest
+		}
+	}
+	return memberName
+}
+
+// Render a struct tag literal, raw unless the tag itself contains a backquote
+func goTag(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// Report whether a Go type is made optional with a pointer; slices, maps and interfaces already
+// have a nil value
+func (m *goModel) pointable(goType string) bool {
+	return !strings.HasPrefix(goType, "[]") && !strings.HasPrefix(goType, "map[") &&
+		goType != "any" && goType != "json.RawMessage"
+}
+
+// Get the Go type of a type. Inline objects are hoisted into types named after their owner and
+// field.
+func (m *goModel) goType(owner, field string, t *ir.Type) string {
+	if t == nil {
+		return "any"
+	}
+	if t.Kind == ir.Ref {
+		return m.refName(t.Ref)
+	}
+	if len(t.Enum) > 0 {
+		return m.goScalarType(t)
+	}
+
+	switch t.Kind {
+	case ir.Intersection:
+		if len(t.Variants) == 1 {
+			return m.goType(owner, field, t.Variants[0])
+		}
+	case ir.Union:
+		return "json.RawMessage"
+	case ir.Array:
+		return "[]" + m.goType(owner, field+"Item", t.Items)
+	case ir.Map:
+		return "map[string]" + m.goType(owner, field+"Value", t.Values)
+	case ir.Object:
+	default:
+		return m.goScalarType(t)
+	}
+
+	name := m.reserve(owner + field)
+	m.structs[name] = true
+	decl := GoDecl{Name: name, Struct: true, Doc: goDoc(name, t.Description, false)}
+	m.structFields(&decl, t)
+	m.decls[name] = decl
+	return name
+}
+
+func (m *goModel) goScalarType(t *ir.Type) string {
+	switch t.Kind {
+	case ir.String:
+		switch t.Format {
+		case "date-time":
+			return "time.Time"
+		case "byte":
+			return "[]byte"
+		}
+		return "string"
+	case ir.Integer:
+		switch t.Format {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		}
+		return "int"
+	case ir.Number:
+		if t.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case ir.Boolean:
+		return "bool"
+	}
+	return "any"
+}
+
+func (m *goModel) refName(name string) string {
+	if goTypeName, ok := m.names[name]; ok {
+		return goTypeName
+	}
+	return "any"
+}
+
+// Generate the Go method for an operation, returning its code and the declaration of its
+// parameters struct (nil if it has no query or header parameters)
+func (m *goModel) operation(serviceName, methodName string, op *ir.Operation) (string, *GoDecl) {
+	var args []string
+	taken := make(map[string]bool)
+	pathArgs := make(map[string]string)
+	var params *GoDecl
+	paramsRequired := false
+	var paramCode strings.Builder
+
+	for _, param := range op.Params {
+		switch param.In {
+		case "path":
+			argName := uniqueName(goLocalName(param.Name), func(n string) bool { return taken[n] })
+			taken[argName] = true
+			pathArgs[param.Name] = argName
+			args = append(args, argName+" "+m.goType(methodName, goName(param.Name), param.Type))
+		case "query", "header":
+			if params == nil {
+				paramsName := m.reserve(methodName + "Params")
+				params = &GoDecl{
+					Name:   paramsName,
+					Struct: true,
+					Doc:    fmt.Sprintf("// %s holds the query and header parameters of %s.%s.", paramsName, serviceName, methodName),
+				}
+			}
+			fieldName := uniqueName(goName(param.Name), func(n string) bool {
+				for _, f := range params.Fields {
+					if f.Name == n {
+						return true
+					}
+				}
+				return false
+			})
+			fieldType := m.goType(methodName, fieldName, param.Type)
+			if !param.Required && m.pointable(fieldType) {
+				fieldType = "*" + fieldType
+			}
+			paramsRequired = paramsRequired || param.Required
+			params.Fields = append(params.Fields, GoField{
+				Doc:  goDoc("", param.Description, param.Deprecated),
+				Name: fieldName,
+				Type: fieldType,
+			})
+
+			target := fmt.Sprintf("query.Add(%s, ", strconv.Quote(param.Name))
+			if param.In == "header" {
+				target = fmt.Sprintf("header.Add(%s, ", strconv.Quote(param.Name))
+			}
+			switch {
+			case strings.HasPrefix(fieldType, "[]") && fieldType != "[]byte":
+				fmt.Fprintf(&paramCode, "for _, v := range params.%s {\n%sformatParam(v))\n}\n", fieldName, target)
+			case strings.HasPrefix(fieldType, "*"):
+				fmt.Fprintf(&paramCode, "if params.%s != nil {\n%sformatParam(*params.%s))\n}\n", fieldName, target, fieldName)
+			default:
+				fmt.Fprintf(&paramCode, "%sformatParam(params.%s))\n", target, fieldName)
+			}
+		}
+	}
+
+	if params != nil {
+		if paramsRequired {
+			args = append(args, "params "+params.Name)
+		} else {
+			args = append(args, "params *"+params.Name)
+		}
+	}
+	// Only JSON bodies are sent; form and multipart bodies are not supported
+	hasBody := op.Body != nil && op.Body.IsJSON() && op.Body.Type != nil
+	if hasBody {
+		args = append(args, "body "+m.goType(methodName, "Body", op.Body.Type))
+	}
+
+	resultType := ""
+	if response := op.SuccessResponse(); response != nil {
+		resultType = m.goType(methodName, "Response", response.Type)
+	}
+	pointerResult := m.structs[resultType]
+
+	var b strings.Builder
+	b.WriteString(goDoc(methodName, operationDocText(op), op.Deprecated))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "func (s *%s) %s(ctx context.Context", serviceName, methodName)
+	for _, arg := range args {
+		b.WriteString(", " + arg)
+	}
+	switch {
+	case resultType == "":
+		b.WriteString(") error {\n")
+	case pointerResult:
+		fmt.Fprintf(&b, ") (*%s, error) {\n", resultType)
+	default:
+		fmt.Fprintf(&b, ") (%s, error) {\n", resultType)
+	}
+
+	fmt.Fprintf(&b, "path := %s\n", goPathExpression(op.Path, pathArgs))
+	query, header := "nil", "nil"
+	if params != nil {
+		query, header = "query", "header"
+		b.WriteString("query := url.Values{}\nheader := http.Header{}\n")
+		if !paramsRequired {
+			b.WriteString("if params != nil {\n")
+			b.WriteString(paramCode.String())
+			b.WriteString("}\n")
+		} else {
+			b.WriteString(paramCode.String())
+		}
+	}
+	body := "nil"
+	if hasBody {
+		body = "body"
+	}
+
+	call := fmt.Sprintf("s.client.do(ctx, http.Method%s, path, %s, %s, %s, %%s)", toTitleCase(strings.ToLower(op.Method)), query, header, body)
+	switch {
+	case resultType == "":
+		fmt.Fprintf(&b, "return "+call+"\n", "nil")
+	case pointerResult:
+		fmt.Fprintf(&b, "var out %s\nif err := "+call+"; err != nil {\nreturn nil, err\n}\nreturn &out, nil\n", resultType, "&out")
+	default:
+		fmt.Fprintf(&b, "var out %s\nerr := "+call+"\nreturn out, err\n", resultType, "&out")
+	}
+	b.WriteString("}\n")
+	return b.String(), params
+}
+
+// Text describing an operation in its doc comment
+func operationDocText(op *ir.Operation) string {
+	text := strings.TrimSpace(op.Summary)
+	if description := strings.TrimSpace(op.Description); description != "" && description != text {
+		if text != "" {
+			text += "\n\n"
+		}
+		text += description
+	}
+	return text
+}
+
+// Build the Go expression for a request path, escaping path parameters
+func goPathExpression(p string, pathArgs map[string]string) string {
+	var parts []string
+	rest := p
+	for {
+		start := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if start < 0 || end < start {
+			break
+		}
+		if start > 0 {
+			parts = append(parts, strconv.Quote(rest[:start]))
+		}
+		name := rest[start+1 : end]
+		if arg, ok := pathArgs[name]; ok {
+			parts = append(parts, fmt.Sprintf("url.PathEscape(formatParam(%s))", arg))
+		} else {
+			parts = append(parts, strconv.Quote(rest[start:end+1]))
+		}
+		rest = rest[end+1:]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// Build a Go doc comment. Declarations (name set) start with their name as Go convention asks;
+// fields and constants keep the description as written.
+func goDoc(name, description string, deprecated bool) string {
+	lines := splitDocText(description)
+	if len(lines) > 0 && name != "" {
+		first := []rune(lines[0])
+		lines[0] = name + " " + string(unicode.ToLower(first[0])) + string(first[1:])
+		if len(first) > 1 && unicode.IsUpper(first[1]) {
+			// Keep acronyms such as "ID of the pet"
+			lines[0] = name + " " + string(first)
+		}
+	}
+	if deprecated {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "Deprecated: deprecated in the API specification.")
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Convert a spec name to an exported Go identifier (pet_id -> PetID)
+func goName(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		lower := strings.ToLower(word)
+		if initialism, ok := goInitialisms[lower]; ok {
+			b.WriteString(initialism)
+		} else {
+			b.WriteString(toTitleCase(lower))
+		}
+	}
+	name := b.String()
+	if name == "" {
+		return "Value"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "V" + name
+	}
+	return name
+}
+
+// Convert a spec name to an unexported Go identifier that does not shadow keywords or locals
+func goLocalName(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return "value"
+	}
+	name := strings.ToLower(words[0]) + strings.TrimPrefix(goName(strings.Join(words, " ")), goName(words[0]))
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "v" + name
+	}
+	if goReservedNames[name] {
+		name += "Param"
+	}
+	return name
+}
+
+// Split a name into words on separators and camelCase boundaries (petID -> pet, ID)
+func splitWords(s string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) && unicode.IsUpper(runes[i])
+			acronymEnd := i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// List the standard library packages a piece of generated code refers to, plus the given ones
+func goImports(code string, always ...string) []string {
+	imports := append([]string{}, always...)
+	for pkg, selector := range map[string]string{
+		"encoding/json": "json.",
+		"net/url":       "url.",
+		"time":          "time.",
+	} {
+		if strings.Contains(code, selector) && !contains(imports, pkg) {
+			imports = append(imports, pkg)
+		}
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// Flatten declarations into text for import detection
+func goDeclsText(decls []GoDecl) string {
+	var b strings.Builder
+	for _, decl := range decls {
+		b.WriteString(decl.Type + "\n")
+		for _, field := range decl.Fields {
+			b.WriteString(field.Type + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,313 @@
+package generator
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for the Go server
+type GoServerTemplateData struct {
+	Package string
+}
+
+type GoHandlersTemplateData struct {
+	Package  string
+	Imports  []string
+	Resource string
+	// Interface is implemented by the user, Register mounts it, Handler adapts it to net/http
+	Interface string
+	Register  string
+	Handler   string
+	Methods   []GoServerMethod
+	Decls     []GoDecl
+	Routes    []GoRoute
+	Handlers  []string
+}
+
+// GoServerMethod is a method of a resource's server interface
+type GoServerMethod struct {
+	Doc      string
+	Name     string
+	Request  string
+	Response string
+}
+
+// GoRoute registers a handler method for a ServeMux pattern (quoted)
+type GoRoute struct {
+	Pattern string
+	Handler string
+}
+
+// goPathParam is the wildcard a path parameter is matched by. A parameter sharing its segment
+// with literal text ({id}.json) takes the whole segment, which is trimmed of Prefix and Suffix.
+type goPathParam struct {
+	Wildcard string
+	Prefix   string
+	Suffix   string
+}
+
+// Names declared by server.go that generated types must not take
+var goServerNames = []string{
+	"HTTPError", "ErrorHandler", "ServerOption", "WithErrorHandler", "DefaultErrorHandler",
+}
+
+// GenerateGoServer generates a Go package with a server interface and net/http routes per
+// resource from the spec
+func GenerateGoServer(config Config) (*Report, error) {
+	api, report, err := prepareSpec(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(config.OutputPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := generateGoServer(api, config); err != nil {
+		return nil, err
+	}
+
+	report.Deprecated = collectDeprecations(api)
+	return report, nil
+}
+
+func generateGoServer(api *ir.API, config Config) error {
+	pkg := goPackageName(config)
+	model := newGoModel(api, config, goServerNames)
+
+	handlersTmpl, err := loadTemplate("go/handlers.tmpl")
+	if err != nil {
+		return err
+	}
+
+	// An operation listed by several resources (several tags) is served by the first one only,
+	// since registering its pattern twice on a mux panics. Patterns that differ only in their
+	// wildcard names conflict the same way.
+	served := make(map[*ir.Operation]bool)
+	shapes := make(map[string]*ir.Operation)
+	fields := goResourceNames(api)
+	for i, resource := range api.Resources {
+		field := fields[i]
+		data := GoHandlersTemplateData{
+			Package:   pkg,
+			Resource:  resource.Name,
+			Interface: model.reserve(field + "ServerInterface"),
+			Register:  model.reserve("Register" + field + "Routes"),
+			Handler:   goLocalName(field) + "Handler",
+		}
+
+		for j, methodName := range goMethodNames(resource) {
+			op := resource.Operations[j]
+			if served[op] {
+				continue
+			}
+			served[op] = true
+
+			pattern, pathParams, shape, err := goServePattern(op)
+			if err != nil {
+				return err
+			}
+			if other, ok := shapes[shape]; ok {
+				return fmt.Errorf("%s %s: the net/http pattern conflicts with %s %s", op.Method, op.Path, other.Method, other.Path)
+			}
+			shapes[shape] = op
+
+			method, decls, handler := model.serverOperation(data.Interface, data.Handler, methodName, op, pathParams)
+			data.Methods = append(data.Methods, method)
+			data.Decls = append(data.Decls, decls...)
+			data.Routes = append(data.Routes, GoRoute{Pattern: strconv.Quote(pattern), Handler: "handle" + methodName})
+			data.Handlers = append(data.Handlers, handler)
+		}
+		if len(data.Methods) == 0 {
+			continue
+		}
+		// The handlers only name types that also appear in the declarations
+		data.Imports = goImports(goDeclsText(data.Decls), "context", "net/http")
+
+		filePath, err := outputPath(config.OutputPath, strings.ToLower(field)+"_server.go")
+		if err != nil {
+			return err
+		}
+		if err := writeGoFile(handlersTmpl, data, filePath); err != nil {
+			return err
+		}
+	}
+
+	if err := writeGoTypes(model, api, pkg, config.OutputPath); err != nil {
+		return err
+	}
+
+	serverTmpl, err := loadTemplate("go/server.tmpl")
+	if err != nil {
+		return err
+	}
+	return writeGoFile(serverTmpl, GoServerTemplateData{Package: pkg}, filepath.Join(config.OutputPath, "server.go"))
+}
+
+// Build the Go 1.22 ServeMux pattern of an operation ("GET /pets/{petID}"), the wildcards of its
+// path parameters, and its shape: the pattern without wildcard names, equal for conflicting
+// patterns
+func goServePattern(op *ir.Operation) (string, map[string]goPathParam, string, error) {
+	params := make(map[string]goPathParam)
+	taken := make(map[string]bool)
+	segments := strings.Split(strings.TrimPrefix(op.Path, "/"), "/")
+	shape := make([]string, len(segments))
+	for i, segment := range segments {
+		start := strings.Index(segment, "{")
+		if start < 0 {
+			segments[i] = url.PathEscape(segment)
+			shape[i] = segments[i]
+			continue
+		}
+		end := strings.Index(segment, "}")
+		if end < start || strings.Contains(segment[end+1:], "{") {
+			return "", nil, "", fmt.Errorf("%s %s: net/http patterns cannot match more than one parameter in a path segment", op.Method, op.Path)
+		}
+		name := segment[start+1 : end]
+		wildcard := uniqueName(goLocalName(name), func(n string) bool { return taken[n] })
+		taken[wildcard] = true
+		params[name] = goPathParam{Wildcard: wildcard, Prefix: segment[:start], Suffix: segment[end+1:]}
+		segments[i] = "{" + wildcard + "}"
+		shape[i] = "{}"
+	}
+
+	method := strings.ToUpper(op.Method)
+	pattern := "/" + strings.Join(segments, "/")
+	shapeText := method + " /" + strings.Join(shape, "/")
+	// A trailing slash would match every path below it
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "{$}"
+		shapeText += "{$}"
+	}
+	return method + " " + pattern, params, shapeText, nil
+}
+
+// Generate the server side of an operation: its interface method, its request and response
+// structs, and the handler method decoding the request and encoding the response
+func (m *goModel) serverOperation(iface, handlerType, methodName string, op *ir.Operation, pathParams map[string]goPathParam) (GoServerMethod, []GoDecl, string) {
+	request := GoDecl{Name: m.reserve(methodName + "Request"), Struct: true}
+	request.Doc = fmt.Sprintf("// %s is the decoded request of %s.%s.", request.Name, iface, methodName)
+	response := GoDecl{Name: m.reserve(methodName + "Response"), Struct: true}
+	status := op.SuccessStatus()
+
+	// Only JSON bodies are decoded; handlers read other bodies from HTTPRequest
+	hasBody := op.Body != nil && op.Body.IsJSON() && op.Body.Type != nil
+	fieldTaken := map[string]bool{"HTTPRequest": true, "Body": hasBody}
+	fail := "h.config.errorHandler(w, r, %s)\nreturn\n"
+
+	var code strings.Builder
+	fmt.Fprintf(&code, "func (h *%s) handle%s(w http.ResponseWriter, r *http.Request) {\n", handlerType, methodName)
+	fmt.Fprintf(&code, "req := %s{HTTPRequest: r}\n", request.Name)
+	if len(op.ParamsIn("query")) > 0 {
+		code.WriteString("query := r.URL.Query()\n")
+	}
+
+	for _, param := range op.Params {
+		var source string
+		switch param.In {
+		case "path":
+			if _, ok := pathParams[param.Name]; !ok {
+				// Declared but missing from the path template
+				continue
+			}
+		case "query":
+			source = fmt.Sprintf("query[%s]", strconv.Quote(param.Name))
+		case "header":
+			source = fmt.Sprintf("r.Header.Values(%s)", strconv.Quote(param.Name))
+		default:
+			continue
+		}
+
+		fieldName := uniqueName(goName(param.Name), func(n string) bool { return fieldTaken[n] })
+		fieldTaken[fieldName] = true
+		fieldType := m.goType(methodName, fieldName, param.Type)
+		if param.In != "path" && !param.Required && m.pointable(fieldType) {
+			fieldType = "*" + fieldType
+		}
+		request.Fields = append(request.Fields, GoField{
+			Doc:  goDoc("", param.Description, param.Deprecated),
+			Name: fieldName,
+			Type: fieldType,
+		})
+
+		name := strconv.Quote(param.Name)
+		target := "req." + fieldName
+		parse := fmt.Sprintf("parseParam(&%s, %s, raw)", target, name)
+		switch {
+		case param.In == "path":
+			wildcard := pathParams[param.Name]
+			value := fmt.Sprintf("r.PathValue(%s)", strconv.Quote(wildcard.Wildcard))
+			if wildcard.Prefix == "" && wildcard.Suffix == "" {
+				fmt.Fprintf(&code, "if err := parseParam(&%s, %s, %s); err != nil {\n"+fail+"}\n", target, name, value, "err")
+			} else {
+				fmt.Fprintf(&code, "if raw, ok := trimPathSegment(%s, %s, %s); !ok {\nhttp.NotFound(w, r)\nreturn\n} else if err := %s; err != nil {\n"+fail+"}\n",
+					value, strconv.Quote(wildcard.Prefix), strconv.Quote(wildcard.Suffix), parse, "err")
+			}
+		case strings.HasPrefix(fieldType, "[]") && fieldType != "[]byte":
+			fmt.Fprintf(&code, "for _, raw := range %s {\nvar item %s\nif err := parseParam(&item, %s, raw); err != nil {\n"+fail+"}\n%s = append(%s, item)\n}\n",
+				source, strings.TrimPrefix(fieldType, "[]"), name, "err", target, target)
+			if param.Required {
+				fmt.Fprintf(&code, "if len(%s) == 0 {\n"+fail+"}\n", target, "missingParam("+name+")")
+			}
+		case strings.HasPrefix(fieldType, "*"):
+			fmt.Fprintf(&code, "if raw, ok := lookupParam(%s); ok {\n%s = new(%s)\nif err := parseParam(%s, %s, raw); err != nil {\n"+fail+"}\n}\n",
+				source, target, strings.TrimPrefix(fieldType, "*"), target, name, "err")
+		case param.Required:
+			fmt.Fprintf(&code, "if raw, ok := lookupParam(%s); !ok {\n"+fail+"} else if err := %s; err != nil {\n"+fail+"}\n",
+				source, "missingParam("+name+")", parse, "err")
+		default:
+			fmt.Fprintf(&code, "if raw, ok := lookupParam(%s); ok {\nif err := %s; err != nil {\n"+fail+"}\n}\n", source, parse, "err")
+		}
+	}
+
+	if hasBody {
+		bodyType := m.goType(methodName, "RequestBody", op.Body.Type)
+		if !op.Body.Required && m.pointable(bodyType) {
+			bodyType = "*" + bodyType
+		}
+		request.Fields = append(request.Fields, GoField{Doc: "// Body is the decoded JSON request body", Name: "Body", Type: bodyType})
+		fmt.Fprintf(&code, "if err := decodeBody(r, &req.Body, %t); err != nil {\n"+fail+"}\n", op.Body.Required, "err")
+	}
+	request.Fields = append(request.Fields, GoField{
+		Doc:  "// HTTPRequest is the incoming request, for what is not decoded above (cookies, other bodies)",
+		Name: "HTTPRequest",
+		Type: "*http.Request",
+	})
+
+	response.Doc = fmt.Sprintf("// %s is the response of %s.%s.", response.Name, iface, methodName)
+	response.Fields = []GoField{
+		{Doc: fmt.Sprintf("// StatusCode defaults to %d", status), Name: "StatusCode", Type: "int"},
+		{Doc: "// Header is added to the response headers", Name: "Header", Type: "http.Header"},
+	}
+	body := "nil"
+	if success := op.SuccessResponse(); success != nil {
+		bodyType := m.goType(methodName, "ResponseBody", success.Type)
+		body = "resp.Body"
+		if m.structs[bodyType] {
+			// A nil struct pointer sends no body
+			bodyType = "*" + bodyType
+			body = "body"
+		}
+		response.Fields = append(response.Fields, GoField{Doc: "// Body is sent as JSON", Name: "Body", Type: bodyType})
+	}
+
+	fmt.Fprintf(&code, "resp, err := h.server.%s(r.Context(), req)\nif err != nil {\n"+fail+"}\n", methodName, "err")
+	if body == "body" {
+		code.WriteString("var body any\nif resp.Body != nil {\nbody = resp.Body\n}\n")
+	}
+	fmt.Fprintf(&code, "writeResponse(w, resp.Header, resp.StatusCode, %d, %s)\n}\n", status, body)
+
+	method := GoServerMethod{
+		Doc:      goDoc(methodName, operationDocText(op), op.Deprecated),
+		Name:     methodName,
+		Request:  request.Name,
+		Response: response.Name,
+	}
+	return method, []GoDecl{request, response}, code.String()
+}
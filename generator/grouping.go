@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resource grouping strategies selected with -group-by
+const (
+	GroupByTag       = "tag"
+	GroupByTags      = "tags"
+	GroupByPath      = "path"
+	GroupByExtension = "extension"
+	GroupByFlat      = "flat"
+)
+
+// ValidateGroupBy checks a -group-by value
+func ValidateGroupBy(strategy string) error {
+	switch strategy {
+	case "", GroupByTag, GroupByTags, GroupByPath, GroupByExtension, GroupByFlat:
+		return nil
+	}
+	return fmt.Errorf("unknown grouping strategy %q (expected tag, tags, path, extension or flat)", strategy)
+}
+
+// ParseResourceRenames parses a "from=to,from=to" list of resource renames
+func ParseResourceRenames(value string) (map[string]string, error) {
+	return parseKeyValueList(value, "resource rename", "from=to")
+}
+
+// Get the resources an operation is generated under, with renames applied
+func resourceNamesForOperation(path string, op *Operation, config Config) []string {
+	var names []string
+	switch config.GroupBy {
+	case GroupByTags:
+		names = op.Tags
+	case GroupByPath:
+		names = []string{pathResourceName(path, config)}
+	case GroupByExtension:
+		if op.XSvegerResource != "" {
+			names = []string{op.XSvegerResource}
+		} else if len(op.Tags) > 0 {
+			names = op.Tags[:1]
+		}
+	case GroupByFlat:
+		names = []string{"default"}
+	default:
+		if len(op.Tags) > 0 {
+			names = op.Tags[:1]
+		}
+	}
+	if len(names) == 0 {
+		// Extract from path
+		names = []string{strings.Split(strings.Trim(path, "/"), "/")[0]}
+	}
+
+	resources := make([]string, 0, len(names))
+	for _, name := range names {
+		if renamed, ok := config.ResourceRenames[name]; ok {
+			name = renamed
+		}
+		if name == "" {
+			name = "default"
+		}
+		name = toIdentifier(name)
+		if !containsFold(resources, name) {
+			resources = append(resources, name)
+		}
+	}
+	return resources
+}
+
+// Get the path segment naming the resource, after stripping the configured prefix
+func pathResourceName(path string, config Config) string {
+	if config.StripPrefix != "" {
+		path = strings.TrimPrefix(path, "/"+strings.Trim(config.StripPrefix, "/"))
+	}
+	segments := splitPath(path)
+	if config.PathSegment < len(segments) {
+		if segment := segments[config.PathSegment]; !strings.HasPrefix(segment, "{") {
+			return segment
+		}
+	}
+	return "default"
+}
@@ -0,0 +1,413 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Build the intermediate model of a spec that went through prepareSpec
+func buildAPI(spec *OpenAPISpec, config Config) *ir.API {
+	api := &ir.API{
+		Title:   spec.Info.Title,
+		Version: spec.Info.Version,
+	}
+	for _, server := range spec.Servers {
+		api.Servers = append(api.Servers, server.URL)
+	}
+
+	schemas := getAllSchemas(spec)
+	for _, name := range sortedKeys(schemas) {
+		api.Types = append(api.Types, &ir.Decl{Name: sanitizeTypeName(name), Type: irType(schemas[name])})
+	}
+
+	// Resource names differing only in case share one resource (and directory)
+	resources := make(map[string]*ir.Resource)
+	for _, path := range sortedPaths(spec) {
+		for _, method := range spec.Paths[path].operations() {
+			op := irOperation(path, method)
+			for _, resourceName := range resourceNamesForOperation(path, method.op, config) {
+				key := strings.ToLower(resourceName)
+				if resources[key] == nil {
+					resources[key] = &ir.Resource{Name: resourceName}
+				}
+				resources[key].Operations = append(resources[key].Operations, op)
+			}
+		}
+	}
+	for _, key := range sortedKeys(resources) {
+		api.Resources = append(api.Resources, resources[key])
+	}
+	return api
+}
+
+func irOperation(path string, method PathOperation) *ir.Operation {
+	op := method.op
+	result := &ir.Operation{
+		ID:          op.OperationID,
+		Method:      method.name,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
+	}
+
+	for _, param := range op.Parameters {
+		if param.In == "body" {
+			// Swagger 2.0 body parameters are always JSON
+			result.Body = &ir.Body{ContentType: "application/json", Required: param.Required, Type: irSchemaType(param.Schema)}
+			continue
+		}
+		result.Params = append(result.Params, &ir.Param{
+			Name:        param.Name,
+			In:          param.In,
+			Required:    param.Required,
+			Deprecated:  param.Deprecated,
+			Description: param.Description,
+			Type:        irType(param.typeSchema()),
+		})
+	}
+	if result.Body == nil && op.RequestBody != nil {
+		contentType, schema := preferredContent(op.RequestBody.Content)
+		result.Body = &ir.Body{ContentType: contentType, Required: op.RequestBody.Required, Type: irSchemaType(schema)}
+	}
+
+	for _, status := range sortedKeys(op.Responses) {
+		response := op.Responses[status]
+		contentType, schema := preferredContent(response.Content)
+		if schema == nil && response.Schema != nil {
+			contentType, schema = "application/json", response.Schema
+		}
+		irResponse := &ir.Response{
+			Status:      status,
+			Description: response.Description,
+			ContentType: contentType,
+			Type:        irSchemaType(schema),
+		}
+		if media, ok := response.Content[contentType]; ok {
+			irResponse.Example = media.Example
+			for name, example := range media.Examples {
+				if irResponse.Examples == nil {
+					irResponse.Examples = make(map[string]any)
+				}
+				irResponse.Examples[name] = example.Value
+			}
+		} else if example, ok := response.Examples[contentType]; ok {
+			irResponse.Example = example
+		}
+		result.Responses = append(result.Responses, irResponse)
+	}
+	return result
+}
+
+// Pick the media type of a request or response: JSON when it has a schema, otherwise the first
+// one with a schema in sorted order, otherwise the first one
+func preferredContent(content map[string]MediaTypeObject) (string, *Schema) {
+	if media, ok := content["application/json"]; ok && media.Schema != nil {
+		return "application/json", media.Schema
+	}
+	contentTypes := sortedKeys(content)
+	for _, contentType := range contentTypes {
+		if schema := content[contentType].Schema; schema != nil {
+			return contentType, schema
+		}
+	}
+	if _, ok := content["application/json"]; ok {
+		return "application/json", nil
+	}
+	if len(contentTypes) > 0 {
+		return contentTypes[0], nil
+	}
+	return "", nil
+}
+
+// Convert a body schema, keeping a missing schema as nil
+func irSchemaType(schema *Schema) *ir.Type {
+	if schema == nil {
+		return nil
+	}
+	return irType(schema)
+}
+
+// Convert a schema to a type. References stay references, and a schema without a type is any.
+func irType(schema *Schema) *ir.Type {
+	if schema == nil {
+		return &ir.Type{Kind: ir.Any}
+	}
+	if schema.Ref != "" {
+		return &ir.Type{Kind: ir.Ref, Ref: getRefName(schema.Ref)}
+	}
+
+	t := &ir.Type{
+		Nullable:    schema.IsNullable(),
+		Format:      schema.Format,
+		Override:    schema.XSvegerType,
+		Minimum:     schema.Minimum,
+		Maximum:     schema.Maximum,
+		MinLength:   schema.MinLength,
+		MaxLength:   schema.MaxLength,
+		Pattern:     schema.Pattern,
+		MinItems:    schema.MinItems,
+		MaxItems:    schema.MaxItems,
+		Description: schema.Description,
+		Default:     schema.Default,
+		Example:     schema.Example,
+		Deprecated:  schema.Deprecated,
+	}
+
+	switch {
+	case len(schema.Enum) > 0:
+		t.Kind = enumKind(schema)
+		t.Enum = schema.Enum
+		names := schema.XEnumVarnames
+		if len(names) != len(schema.Enum) {
+			names = schema.XEnumNames
+		}
+		if len(names) == len(schema.Enum) {
+			t.EnumNames = names
+		}
+		t.EnumDescriptions = schema.XEnumDescriptions
+	case len(schema.AllOf) > 0:
+		t.Kind = ir.Intersection
+		for _, sub := range schema.AllOf {
+			t.Variants = append(t.Variants, irType(sub))
+		}
+		// Properties next to allOf are one more member of the intersection
+		if len(schema.Properties) > 0 {
+			t.Variants = append(t.Variants, &ir.Type{Kind: ir.Object, Fields: irFields(schema)})
+		}
+	case len(schema.OneOf) > 0 || len(schema.AnyOf) > 0:
+		t.Kind = ir.Union
+		for _, sub := range append(append([]*Schema{}, schema.OneOf...), schema.AnyOf...) {
+			t.Variants = append(t.Variants, irType(sub))
+		}
+	case len(schema.Properties) > 0:
+		t.Kind = ir.Object
+		t.Fields = irFields(schema)
+	default:
+		switch schema.Type {
+		case "string":
+			t.Kind = ir.String
+		case "integer":
+			t.Kind = ir.Integer
+		case "number":
+			t.Kind = ir.Number
+		case "boolean":
+			t.Kind = ir.Boolean
+		case "array":
+			t.Kind = ir.Array
+			t.Items = irType(schema.Items)
+		case "object":
+			t.Kind = ir.Map
+			t.Values = &ir.Type{Kind: ir.Any}
+		default:
+			t.Kind = ir.Any
+		}
+	}
+	switch t.Kind {
+	case ir.String, ir.Integer, ir.Number, ir.Boolean:
+	default:
+		// Only scalars are overridden; the validator rejects x-sveger-type on other schemas
+		t.Override = ""
+	}
+	return t
+}
+
+// Convert an object schema's properties to fields sorted by name
+func irFields(schema *Schema) []*ir.Field {
+	fields := make([]*ir.Field, 0, len(schema.Properties))
+	for _, name := range sortedKeys(schema.Properties) {
+		fields = append(fields, &ir.Field{
+			Name:     name,
+			Required: contains(schema.Required, name),
+			Type:     irType(schema.Properties[name]),
+		})
+	}
+	return fields
+}
+
+// Get the scalar kind of an enum, from its type or else from its values
+func enumKind(schema *Schema) ir.Kind {
+	switch schema.Type {
+	case "string":
+		return ir.String
+	case "integer":
+		return ir.Integer
+	case "number":
+		return ir.Number
+	case "boolean":
+		return ir.Boolean
+	}
+
+	kind := ir.Kind("")
+	for _, val := range schema.Enum {
+		var valueKind ir.Kind
+		switch v := val.(type) {
+		case nil:
+			continue
+		case string:
+			valueKind = ir.String
+		case bool:
+			valueKind = ir.Boolean
+		case int, int64, uint64:
+			valueKind = ir.Integer
+		case float64:
+			valueKind = ir.Number
+			if v == math.Trunc(v) {
+				valueKind = ir.Integer
+			}
+		default:
+			return ir.Any
+		}
+		switch {
+		case kind == "" || kind == valueKind:
+			kind = valueKind
+		case (kind == ir.Integer && valueKind == ir.Number) || (kind == ir.Number && valueKind == ir.Integer):
+			kind = ir.Number
+		default:
+			return ir.Any
+		}
+	}
+	if kind == "" {
+		return ir.Any
+	}
+	return kind
+}
+
+// Write the intermediate model as indented JSON, for -dump-ir
+func writeIR(api *ir.API, path string) error {
+	content, err := json.MarshalIndent(api, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, string(content)+"\n")
+}
+
+// Get the types an operation uses: its parameters, request body and responses
+func operationTypes(op *ir.Operation) []*ir.Type {
+	var types []*ir.Type
+	for _, param := range op.Params {
+		types = append(types, param.Type)
+	}
+	if op.Body != nil && op.Body.Type != nil {
+		types = append(types, op.Body.Type)
+	}
+	for _, response := range op.Responses {
+		if response.Type != nil {
+			types = append(types, response.Type)
+		}
+	}
+	return types
+}
+
+// Get the fields of a form an operation sends: its Swagger 2.0 formData parameters, or the
+// properties of an application/x-www-form-urlencoded or multipart/form-data body. Forms with a
+// file are multipart. ok is false when the operation sends no form, and err is set for a form body
+// whose schema is not an object.
+func formFields(op *ir.Operation, decls map[string]*ir.Decl) (fields []*ir.Field, multipart, ok bool, err error) {
+	if params := op.ParamsIn("formData"); len(params) > 0 {
+		for _, param := range params {
+			// The parameter's description and deprecation describe the field
+			fieldType := *param.Type
+			fieldType.Description = param.Description
+			fieldType.Deprecated = fieldType.Deprecated || param.Deprecated
+			fields = append(fields, &ir.Field{Name: param.Name, Required: param.Required, Type: &fieldType})
+		}
+	} else if op.Body != nil && (op.Body.ContentType == "application/x-www-form-urlencoded" || op.Body.ContentType == "multipart/form-data") {
+		multipart = op.Body.ContentType == "multipart/form-data"
+		if op.Body.Type != nil {
+			var flat bool
+			if fields, flat = flattenFields(op.Body.Type, decls, make(map[string]bool)); !flat {
+				return nil, false, false, fmt.Errorf("operation %s: the %s request body must be an object", op.ID, op.Body.ContentType)
+			}
+		}
+	} else {
+		return nil, false, false, nil
+	}
+
+	for _, field := range fields {
+		multipart = multipart || isFileType(field.Type)
+	}
+	return fields, multipart, true, nil
+}
+
+// Report whether a form field is a file upload: a binary string, or an array of them
+func isFileType(t *ir.Type) bool {
+	if t.Kind == ir.Array && t.Items != nil {
+		t = t.Items
+	}
+	return t.Kind == ir.String && t.Format == "binary"
+}
+
+// Get the names of the declarations a type refers to directly, sorted
+func typeRefs(t *ir.Type) []string {
+	seen := make(map[string]bool)
+	ir.Walk(t, func(t *ir.Type) {
+		if t.Kind == ir.Ref {
+			seen[t.Ref] = true
+		}
+	})
+	return sortedKeys(seen)
+}
+
+// Index the declarations of an API by name
+func declsByName(api *ir.API) map[string]*ir.Decl {
+	decls := make(map[string]*ir.Decl, len(api.Types))
+	for _, decl := range api.Types {
+		decls[decl.Name] = decl
+	}
+	return decls
+}
+
+// Get the fields of an object or of an allOf of objects, for languages that declare allOf as one
+// class. A field declared again overrides the earlier one, and is required if either is.
+func flattenFields(t *ir.Type, decls map[string]*ir.Decl, seen map[string]bool) ([]*ir.Field, bool) {
+	switch t.Kind {
+	case ir.Object:
+		return t.Fields, true
+	case ir.Ref:
+		decl := decls[t.Ref]
+		if decl == nil || seen[t.Ref] {
+			return nil, false
+		}
+		seen[t.Ref] = true
+		defer delete(seen, t.Ref)
+		return flattenFields(decl.Type, decls, seen)
+	case ir.Intersection:
+		var fields []*ir.Field
+		index := make(map[string]int)
+		for _, variant := range t.Variants {
+			variantFields, ok := flattenFields(variant, decls, seen)
+			if !ok {
+				return nil, false
+			}
+			for _, f := range variantFields {
+				if i, ok := index[f.Name]; ok {
+					merged := *f
+					merged.Required = f.Required || fields[i].Required
+					fields[i] = &merged
+					continue
+				}
+				index[f.Name] = len(fields)
+				fields = append(fields, f)
+			}
+		}
+		return fields, true
+	}
+	return nil, false
+}
+
+// Get the base URL the generated clients default to: -base-url, else the first server
+func defaultBaseURL(api *ir.API, config Config) string {
+	if config.BaseURL != "" {
+		return config.BaseURL
+	}
+	if len(api.Servers) > 0 && api.Servers[0] != "" {
+		return api.Servers[0]
+	}
+	return "https://api.example.com"
+}
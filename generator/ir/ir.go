@@ -0,0 +1,211 @@
+// Package ir is the language-neutral model of an API that the generators emit code from. It is
+// built once from the parsed spec, after filtering and name normalization, and holds no
+// target-specific type names: every generator renders Types and Operations in its own syntax.
+package ir
+
+import "strconv"
+
+// API is the model of a whole spec
+type API struct {
+	Title   string   `json:"title,omitempty"`
+	Version string   `json:"version,omitempty"`
+	Servers []string `json:"servers,omitempty"`
+	// Types are the named component schemas, sorted by name
+	Types []*Decl `json:"types"`
+	// Resources group the operations by the -group-by strategy, sorted by name ignoring case
+	Resources []*Resource `json:"resources"`
+}
+
+// Decl is a named type
+type Decl struct {
+	Name string `json:"name"`
+	Type *Type  `json:"type"`
+}
+
+// Resource is a group of operations generated together (a TypeScript resource, a Go service)
+type Resource struct {
+	Name       string       `json:"name"`
+	Operations []*Operation `json:"operations"`
+}
+
+type Operation struct {
+	ID          string   `json:"id"`
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Summary     string   `json:"summary,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	// Params are in declaration order
+	Params []*Param `json:"params,omitempty"`
+	Body   *Body    `json:"body,omitempty"`
+	// Responses are sorted by status code
+	Responses []*Response `json:"responses,omitempty"`
+}
+
+// ParamsIn returns the parameters in a location (path, query, header, cookie, formData)
+func (o *Operation) ParamsIn(in string) []*Param {
+	var params []*Param
+	for _, param := range o.Params {
+		if param.In == in {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// Response returns the response with a status code, or nil
+func (o *Operation) Response(status string) *Response {
+	for _, response := range o.Responses {
+		if response.Status == status {
+			return response
+		}
+	}
+	return nil
+}
+
+// SuccessResponse returns the 200 or 201 response that has a body, or nil
+func (o *Operation) SuccessResponse() *Response {
+	for _, status := range []string{"200", "201"} {
+		if response := o.Response(status); response != nil && response.Type != nil {
+			return response
+		}
+	}
+	return nil
+}
+
+// SuccessStatus returns the status a server answers with by default: the SuccessResponse status,
+// else the first 2xx response, else 200
+func (o *Operation) SuccessStatus() int {
+	if response := o.SuccessResponse(); response != nil {
+		if status, err := strconv.Atoi(response.Status); err == nil {
+			return status
+		}
+	}
+	for _, response := range o.Responses {
+		if status, err := strconv.Atoi(response.Status); err == nil && status >= 200 && status < 300 {
+			return status
+		}
+	}
+	return 200
+}
+
+type Param struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Required    bool   `json:"required,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        *Type  `json:"type"`
+}
+
+// Body is a request body. Type describes the ContentType payload and is nil when the spec gives
+// no schema.
+type Body struct {
+	ContentType string `json:"contentType"`
+	Required    bool   `json:"required,omitempty"`
+	Type        *Type  `json:"type,omitempty"`
+}
+
+// IsJSON reports whether the body is sent as JSON
+func (b *Body) IsJSON() bool {
+	return b.ContentType == "application/json"
+}
+
+// Response is the response for a status code. ContentType is the preferred media type (JSON when
+// offered) and Type its schema, both empty for responses without a body. Example and the named
+// Examples are the examples given for that media type.
+type Response struct {
+	Status      string         `json:"status"`
+	Description string         `json:"description,omitempty"`
+	ContentType string         `json:"contentType,omitempty"`
+	Type        *Type          `json:"type,omitempty"`
+	Example     any            `json:"example,omitempty"`
+	Examples    map[string]any `json:"examples,omitempty"`
+}
+
+// Kind is the shape of a Type
+type Kind string
+
+const (
+	Any     Kind = "any"
+	String  Kind = "string"
+	Integer Kind = "integer"
+	Number  Kind = "number"
+	Boolean Kind = "boolean"
+	// Array has Items, Map has Values (a free-form object), Object has Fields
+	Array  Kind = "array"
+	Map    Kind = "map"
+	Object Kind = "object"
+	// Ref refers to the Decl named Ref
+	Ref Kind = "ref"
+	// Union (oneOf, anyOf) and Intersection (allOf) combine their Variants
+	Union        Kind = "union"
+	Intersection Kind = "intersection"
+)
+
+// Type is a node of the type graph. Named types are only reached through Ref, so the graph of a
+// recursive schema is finite.
+type Type struct {
+	Kind     Kind     `json:"kind"`
+	Ref      string   `json:"ref,omitempty"`
+	Items    *Type    `json:"items,omitempty"`
+	Values   *Type    `json:"values,omitempty"`
+	Fields   []*Field `json:"fields,omitempty"`
+	Variants []*Type  `json:"variants,omitempty"`
+	Nullable bool     `json:"nullable,omitempty"`
+	// Format is the spec format (date-time, int64, uuid, ...) and Override a type requested with
+	// x-sveger-type; targets map them to their own types
+	Format   string `json:"format,omitempty"`
+	Override string `json:"override,omitempty"`
+	// Enum restricts a scalar to these values. EnumNames (x-enum-varnames, x-enumNames) and
+	// EnumDescriptions (x-enum-descriptions) are in the same order.
+	Enum             []any    `json:"enum,omitempty"`
+	EnumNames        []string `json:"enumNames,omitempty"`
+	EnumDescriptions []string `json:"enumDescriptions,omitempty"`
+	// Validation constraints
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	MinItems  *int     `json:"minItems,omitempty"`
+	MaxItems  *int     `json:"maxItems,omitempty"`
+	// Documentation
+	Description string `json:"description,omitempty"`
+	Default     any    `json:"default,omitempty"`
+	Example     any    `json:"example,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+}
+
+// Field is a property of an Object
+type Field struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required,omitempty"`
+	Type     *Type  `json:"type"`
+}
+
+// IsScalar reports whether the type is a string, number, integer or boolean
+func (t *Type) IsScalar() bool {
+	switch t.Kind {
+	case String, Integer, Number, Boolean:
+		return true
+	}
+	return false
+}
+
+// Walk visits a type and the types nested in it, without following references
+func Walk(t *Type, visit func(*Type)) {
+	if t == nil {
+		return
+	}
+	visit(t)
+	Walk(t.Items, visit)
+	Walk(t.Values, visit)
+	for _, field := range t.Fields {
+		Walk(field.Type, visit)
+	}
+	for _, variant := range t.Variants {
+		Walk(variant, visit)
+	}
+}
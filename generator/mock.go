@@ -0,0 +1,536 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// MockServer serves every operation of a spec with example responses, for `sveger mock`. Requests
+// are validated against the spec first, and a "Prefer: code=404" header picks another response.
+type MockServer struct {
+	decls  map[string]*ir.Decl
+	routes []*mockRoute
+}
+
+type mockRoute struct {
+	op      *ir.Operation
+	pattern *regexp.Regexp
+	// params names the pattern's groups
+	params []string
+}
+
+// MockIssue is one problem found in a request
+type MockIssue struct {
+	// In is where the problem is: path, query, header, cookie or body
+	In string `json:"in"`
+	// Name is the parameter, Pointer the JSON pointer into the body
+	Name    string `json:"name,omitempty"`
+	Pointer string `json:"pointer,omitempty"`
+	Message string `json:"message"`
+}
+
+type mockError struct {
+	Error   string      `json:"error"`
+	Details []MockIssue `json:"details,omitempty"`
+}
+
+// NewMockServer loads the spec of config and builds a mock server for its operations
+func NewMockServer(config Config) (*MockServer, error) {
+	api, _, err := prepareSpec(config)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &MockServer{decls: declsByName(api)}
+	// An operation listed by several resources is routed once
+	seen := make(map[*ir.Operation]bool)
+	for _, resource := range api.Resources {
+		for _, op := range resource.Operations {
+			if seen[op] {
+				continue
+			}
+			seen[op] = true
+			server.routes = append(server.routes, newMockRoute(op))
+		}
+	}
+	// Routes with fewer parameters match first, so that /pets/mine wins over /pets/{id}
+	sort.SliceStable(server.routes, func(i, j int) bool {
+		a, b := server.routes[i], server.routes[j]
+		if len(a.params) != len(b.params) {
+			return len(a.params) < len(b.params)
+		}
+		return a.op.Path < b.op.Path
+	})
+	return server, nil
+}
+
+// Match a path template against escaped request paths, with or without a trailing slash
+func newMockRoute(op *ir.Operation) *mockRoute {
+	route := &mockRoute{op: op}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	rest := op.Path
+	for _, match := range routeParamPattern.FindAllStringSubmatchIndex(op.Path, -1) {
+		offset := len(op.Path) - len(rest)
+		pattern.WriteString(regexp.QuoteMeta(escapePathLiteral(rest[:match[0]-offset])))
+		pattern.WriteString("([^/]+)")
+		route.params = append(route.params, op.Path[match[2]:match[3]])
+		rest = op.Path[match[1]:]
+	}
+	pattern.WriteString(regexp.QuoteMeta(escapePathLiteral(strings.TrimSuffix(rest, "/"))))
+	pattern.WriteString("/?$")
+	route.pattern = regexp.MustCompile(pattern.String())
+	return route
+}
+
+// Escape the literal text of a path template as it appears in request paths
+func escapePathLiteral(s string) string {
+	segments := strings.Split(s, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// Routes lists the served operations as "METHOD /path"
+func (s *MockServer) Routes() []string {
+	routes := make([]string, 0, len(s.routes))
+	for _, route := range s.routes {
+		routes = append(routes, strings.ToUpper(route.op.Method)+" "+route.op.Path)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browser apps served from another origin, such as a dev server, call the mock directly
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var allowed []string
+	for _, route := range s.routes {
+		match := route.pattern.FindStringSubmatch(r.URL.EscapedPath())
+		if match == nil {
+			continue
+		}
+		if !strings.EqualFold(route.op.Method, r.Method) {
+			allowed = append(allowed, strings.ToUpper(route.op.Method))
+			continue
+		}
+		s.serve(w, r, route, match[1:])
+		return
+	}
+	if len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeMock(w, r, http.StatusMethodNotAllowed, "", mockError{Error: fmt.Sprintf("method %s not allowed", r.Method)})
+		return
+	}
+	writeMock(w, r, http.StatusNotFound, "", mockError{Error: "no operation matches " + r.Method + " " + r.URL.Path})
+}
+
+func (s *MockServer) serve(w http.ResponseWriter, r *http.Request, route *mockRoute, values []string) {
+	pathValues := make(map[string]string)
+	for i, name := range route.params {
+		value, err := url.PathUnescape(values[i])
+		if err != nil {
+			value = values[i]
+		}
+		pathValues[name] = value
+	}
+	if issues := s.validateRequest(r, route.op, pathValues); len(issues) > 0 {
+		writeMock(w, r, http.StatusBadRequest, "", mockError{Error: "request validation failed", Details: issues})
+		return
+	}
+
+	prefer := parsePrefer(r.Header.Get("Prefer"))
+	response, status, err := pickMockResponse(route.op, prefer["code"])
+	if err != nil {
+		writeMock(w, r, http.StatusBadRequest, "", mockError{Error: err.Error()})
+		return
+	}
+	if response == nil || (response.Type == nil && response.Example == nil && len(response.Examples) == 0) {
+		writeMock(w, r, status, "", nil)
+		return
+	}
+	writeMock(w, r, status, response.ContentType, responseExample(response, prefer["example"], s.decls))
+}
+
+// Parse a Prefer header, e.g. "code=404, example=notFound"
+func parsePrefer(header string) map[string]string {
+	preferences := make(map[string]string)
+	for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		preferences[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+	return preferences
+}
+
+// Pick the response to answer with: the one for the preferred code, else the first 2xx one. A
+// preferred code the spec does not list is answered by its default response, else without a body.
+func pickMockResponse(op *ir.Operation, code string) (*ir.Response, int, error) {
+	if code != "" {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			return nil, 0, fmt.Errorf("invalid Prefer code %q", code)
+		}
+		if response := op.Response(code); response != nil {
+			return response, status, nil
+		}
+		return op.Response("default"), status, nil
+	}
+	for _, response := range op.Responses {
+		if strings.HasPrefix(response.Status, "2") {
+			status, err := strconv.Atoi(response.Status)
+			if err != nil {
+				// A range such as 2XX
+				status = http.StatusOK
+			}
+			return response, status, nil
+		}
+	}
+	if response := op.Response("default"); response != nil {
+		return response, http.StatusOK, nil
+	}
+	return nil, http.StatusOK, nil
+}
+
+// Write a response and log it. Bodies are sent as JSON unless the content type is another one and
+// the body is text.
+func writeMock(w http.ResponseWriter, r *http.Request, status int, contentType string, body any) {
+	log.Printf("%s %s -> %d", r.Method, r.URL.RequestURI(), status)
+	if body == nil || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.WriteHeader(status)
+		return
+	}
+	if text, ok := body.(string); ok && contentType != "" && !isJSONMediaType(contentType) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, text)
+		return
+	}
+	if contentType == "" || !isJSONMediaType(contentType) {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	// The status is already sent, so an encoding error cannot be reported to the client
+	_ = encoder.Encode(body)
+}
+
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// Check a request's parameters and JSON body against the operation
+func (s *MockServer) validateRequest(r *http.Request, op *ir.Operation, pathValues map[string]string) []MockIssue {
+	var issues []MockIssue
+	query := r.URL.Query()
+	for _, param := range op.Params {
+		var values []string
+		switch param.In {
+		case "path":
+			if value, ok := pathValues[param.Name]; ok {
+				values = []string{value}
+			}
+		case "query":
+			values = query[param.Name]
+		case "header":
+			values = r.Header.Values(param.Name)
+		case "cookie":
+			if cookie, err := r.Cookie(param.Name); err == nil {
+				values = []string{cookie.Value}
+			}
+		default:
+			continue
+		}
+		add := func(pointer, message string) {
+			issues = append(issues, MockIssue{In: param.In, Name: param.Name, Pointer: pointer, Message: message})
+		}
+		if len(values) == 0 {
+			if param.Required || param.In == "path" {
+				add("", "is required")
+			}
+			continue
+		}
+		value, err := parseMockParam(resolveRef(param.Type, s.decls), values, s.decls)
+		if err != "" {
+			add("", err)
+			continue
+		}
+		s.validateValue(param.Type, value, "", add)
+	}
+
+	if op.Body == nil {
+		return issues
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return append(issues, MockIssue{In: "body", Message: "cannot read the body: " + err.Error()})
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		if op.Body.Required {
+			issues = append(issues, MockIssue{In: "body", Message: "is required"})
+		}
+		return issues
+	}
+	if !op.Body.IsJSON() || op.Body.Type == nil {
+		// Only JSON bodies are validated
+		return issues
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "" && !isJSONMediaType(contentType) {
+		return append(issues, MockIssue{In: "header", Name: "Content-Type", Message: "must be application/json"})
+	}
+	var body any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return append(issues, MockIssue{In: "body", Message: "invalid JSON: " + err.Error()})
+	}
+	s.validateValue(op.Body.Type, body, "", func(pointer, message string) {
+		issues = append(issues, MockIssue{In: "body", Pointer: pointer, Message: message})
+	})
+	return issues
+}
+
+// Convert parameter text to the value it stands for: a number, a boolean, a list for array
+// parameters (repeated or comma-separated), else the text
+func parseMockParam(t *ir.Type, values []string, decls map[string]*ir.Decl) (any, string) {
+	if t.Kind == ir.Array {
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		items := make([]any, 0, len(values))
+		for _, value := range values {
+			item, err := parseMockParam(resolveRef(t.Items, decls), []string{value}, decls)
+			if err != "" {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, ""
+	}
+	value := values[0]
+	switch t.Kind {
+	case ir.Integer, ir.Number:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, "must be " + kindName(t.Kind)
+		}
+		return n, ""
+	case ir.Boolean:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, "must be a boolean"
+		}
+		return b, ""
+	}
+	return value, ""
+}
+
+// Check a decoded JSON value against a type, reporting each problem with its JSON pointer
+func (s *MockServer) validateValue(t *ir.Type, value any, pointer string, add func(pointer, message string)) {
+	t = resolveRef(t, s.decls)
+	if value == nil {
+		if !t.Nullable && t.Kind != ir.Any {
+			add(pointer, "must not be null")
+		}
+		return
+	}
+	if len(t.Enum) > 0 {
+		if !enumContains(t.Enum, value) {
+			add(pointer, fmt.Sprintf("must be one of %s", enumList(t.Enum)))
+		}
+		return
+	}
+
+	switch t.Kind {
+	case ir.String:
+		text, ok := value.(string)
+		if !ok {
+			add(pointer, "must be a string")
+			return
+		}
+		length := utf8.RuneCountInString(text)
+		if t.MinLength != nil && length < *t.MinLength {
+			add(pointer, fmt.Sprintf("must be at least %d characters", *t.MinLength))
+		}
+		if t.MaxLength != nil && length > *t.MaxLength {
+			add(pointer, fmt.Sprintf("must be at most %d characters", *t.MaxLength))
+		}
+		if t.Pattern != "" {
+			if pattern, err := regexp.Compile(t.Pattern); err == nil && !pattern.MatchString(text) {
+				add(pointer, "must match the pattern "+t.Pattern)
+			}
+		}
+		if message := checkFormat(t.Format, text); message != "" {
+			add(pointer, message)
+		}
+	case ir.Integer, ir.Number:
+		n, ok := value.(float64)
+		if !ok || (t.Kind == ir.Integer && n != math.Trunc(n)) {
+			add(pointer, "must be "+kindName(t.Kind))
+			return
+		}
+		if t.Minimum != nil && n < *t.Minimum {
+			add(pointer, "must be at least "+strconv.FormatFloat(*t.Minimum, 'f', -1, 64))
+		}
+		if t.Maximum != nil && n > *t.Maximum {
+			add(pointer, "must be at most "+strconv.FormatFloat(*t.Maximum, 'f', -1, 64))
+		}
+	case ir.Boolean:
+		if _, ok := value.(bool); !ok {
+			add(pointer, "must be a boolean")
+		}
+	case ir.Array:
+		items, ok := value.([]any)
+		if !ok {
+			add(pointer, "must be an array")
+			return
+		}
+		if t.MinItems != nil && len(items) < *t.MinItems {
+			add(pointer, fmt.Sprintf("must have at least %d items", *t.MinItems))
+		}
+		if t.MaxItems != nil && len(items) > *t.MaxItems {
+			add(pointer, fmt.Sprintf("must have at most %d items", *t.MaxItems))
+		}
+		for i, item := range items {
+			s.validateValue(t.Items, item, pointer+"/"+strconv.Itoa(i), add)
+		}
+	case ir.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			add(pointer, "must be an object")
+			return
+		}
+		for _, key := range sortedKeys(object) {
+			s.validateValue(t.Values, object[key], pointer+"/"+jsonPointerToken(key), add)
+		}
+	case ir.Object:
+		object, ok := value.(map[string]any)
+		if !ok {
+			add(pointer, "must be an object")
+			return
+		}
+		for _, f := range t.Fields {
+			fieldValue, present := object[f.Name]
+			if !present {
+				if f.Required {
+					add(pointer, fmt.Sprintf("missing required property %q", f.Name))
+				}
+				continue
+			}
+			s.validateValue(f.Type, fieldValue, pointer+"/"+jsonPointerToken(f.Name), add)
+		}
+	case ir.Intersection:
+		for _, variant := range t.Variants {
+			s.validateValue(variant, value, pointer, add)
+		}
+	case ir.Union:
+		for _, variant := range t.Variants {
+			valid := true
+			s.validateValue(variant, value, pointer, func(string, string) { valid = false })
+			if valid {
+				return
+			}
+		}
+		add(pointer, "does not match any of the allowed schemas")
+	}
+}
+
+// Check the formats the mock can verify
+func checkFormat(format, text string) string {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, text)
+	case "date":
+		_, err = time.Parse("2006-01-02", text)
+	case "uuid":
+		if !uuidPattern.MatchString(text) {
+			return "must be a uuid"
+		}
+	case "email":
+		if !strings.Contains(text, "@") {
+			return "must be an email address"
+		}
+	}
+	if err != nil {
+		return "must be a " + format
+	}
+	return ""
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Name a numeric kind with its article, as in "must be an integer"
+func kindName(kind ir.Kind) string {
+	if kind == ir.Integer {
+		return "an integer"
+	}
+	return "a number"
+}
+
+// Escape a property name for a JSON pointer (RFC 6901)
+func jsonPointerToken(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+// Report whether an enum lists a value, comparing numbers by value
+func enumContains(values []any, value any) bool {
+	n, isNumber := toFloat(value)
+	for _, candidate := range values {
+		if m, ok := toFloat(candidate); ok && isNumber {
+			if m == n {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func enumList(values []any) string {
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Sprint(values)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,211 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for a resource's MSW handlers
+type MockResourceData struct {
+	ResourceName      string
+	ResourceNameLower string
+	FileName          string
+	Handlers          string
+	Codecs            bool
+	Operations        []MockOperationDef
+}
+
+type MockOperationDef struct {
+	Name   string
+	Doc    string
+	Method string
+	// Handler and Response name the handler factory and the default response body, which is left
+	// out when there is none
+	Handler  string
+	Response string
+	// Path is the quoted MSW pattern, matching the path under any base URL
+	Path   string
+	Status int
+	// ContentType is quoted; Body is the default response as a JavaScript literal
+	ContentType  string
+	Body         string
+	ResponseType string
+	// ResponseCodec encodes an override body of a mapped type (Date, bigint) for the wire
+	ResponseCodec string
+	// Params counts the path parameters, to register static paths before parameterized ones
+	Params int
+}
+
+// Names exported by the runtime and the index that generated handlers must not take
+var mockReservedNames = []string{
+	"MockReply", "MockErrorReply", "MockResolverInfo", "MockOverride", "MockDefinition", "mockResolver",
+	"handlers",
+}
+
+// Generate MSW request handlers for -mocks in mocks/, answering with examples from the spec
+func generateMockHandlers(api *ir.API, config Config) error {
+	mocksPath := filepath.Join(config.OutputPath, "mocks")
+	if err := os.MkdirAll(mocksPath, 0755); err != nil {
+		return err
+	}
+
+	resourceTmpl, err := loadTemplate("mocks/resource.tmpl")
+	if err != nil {
+		return err
+	}
+
+	decls := declsByName(api)
+	codecNeeds := findSchemasNeedingCodecs(api, config)
+	taken := make(map[string]bool)
+	for _, name := range mockReservedNames {
+		taken[name] = true
+	}
+	reserve := func(name string) string {
+		name = uniqueName(name, func(n string) bool { return taken[n] })
+		taken[name] = true
+		return name
+	}
+
+	// As with the route handlers, an operation listed by several resources is mocked by the first
+	// one only
+	mocked := make(map[*ir.Operation]bool)
+	var resources []MockResourceData
+	for _, resource := range api.Resources {
+		data := MockResourceData{
+			ResourceName:      toTitleCase(resource.Name),
+			ResourceNameLower: strings.ToLower(resource.Name),
+			FileName:          fileSegment(strings.ToLower(resource.Name)),
+		}
+		data.Handlers = reserve(data.ResourceNameLower + "Handlers")
+
+		for _, op := range resource.Operations {
+			if mocked[op] {
+				continue
+			}
+			mocked[op] = true
+			operation, err := mockOperation(op, config, decls, codecNeeds)
+			if err != nil {
+				return err
+			}
+			operation.Handler = reserve(op.ID + "Handler")
+			if operation.Body != "" {
+				operation.Response = reserve(op.ID + "Response")
+			}
+			data.Codecs = data.Codecs || operation.ResponseCodec != ""
+			data.Operations = append(data.Operations, operation)
+		}
+		if len(data.Operations) == 0 {
+			continue
+		}
+
+		// MSW answers with the first matching handler, so /pets/mine goes before /pets/:petId
+		sort.SliceStable(data.Operations, func(i, j int) bool {
+			return data.Operations[i].Params < data.Operations[j].Params
+		})
+
+		content, err := executeTemplate(resourceTmpl, data)
+		if err != nil {
+			return err
+		}
+		filePath, err := outputPath(mocksPath, data.FileName+".ts")
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		resources = append(resources, data)
+	}
+
+	// Every handler in one list, ordered across resources the same way
+	type handlerRef struct {
+		File, Handler string
+		Params        int
+	}
+	var all []handlerRef
+	for _, resource := range resources {
+		for _, op := range resource.Operations {
+			all = append(all, handlerRef{resource.FileName, op.Handler, op.Params})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Params < all[j].Params })
+
+	runtimeTmpl, err := loadTemplate("mocks/runtime.tmpl")
+	if err != nil {
+		return err
+	}
+	content, err := executeTemplate(runtimeTmpl, struct{}{})
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(mocksPath, "runtime.ts"), content); err != nil {
+		return err
+	}
+
+	indexTmpl, err := loadTemplate("mocks/index.tmpl")
+	if err != nil {
+		return err
+	}
+	content, err = executeTemplate(indexTmpl, struct {
+		Resources []MockResourceData
+		Handlers  []handlerRef
+	}{resources, all})
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(mocksPath, "index.ts"), content)
+}
+
+// Describe an operation for the mocks templates, with its default response rendered as a literal
+func mockOperation(op *ir.Operation, config Config, decls map[string]*ir.Decl, codecNeeds map[string]bool) (MockOperationDef, error) {
+	lines := splitDocText(operationDocText(op))
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, fmt.Sprintf("Mocks %s %s", op.Method, op.Path))
+	if op.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+
+	routePath, routeNames := colonRoutePath(op.Path)
+	operation := MockOperationDef{
+		Name:         op.ID,
+		Doc:          docComment(lines, ""),
+		Method:       strings.ToLower(op.Method),
+		Path:         jsSingleQuoted("*" + routePath),
+		Status:       op.SuccessStatus(),
+		ResponseType: "void",
+		Params:       len(routeNames),
+	}
+
+	if response := op.SuccessResponse(); response != nil {
+		if response.ContentType != "" {
+			operation.ContentType = jsSingleQuoted(response.ContentType)
+		}
+		operation.ResponseType = tsType(response.Type, config, false)
+		operation.ResponseCodec = getOperationCodec(response.Type, config, codecNeeds)
+		if example := responseExample(response, "", decls); example != nil {
+			body, err := jsValue(example)
+			if err != nil {
+				return operation, fmt.Errorf("example response of %s: %w", op.ID, err)
+			}
+			operation.Body = body
+		}
+	}
+	return operation, nil
+}
+
+// Render JSON data as an indented JavaScript literal
+func jsValue(value any) (string, error) {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,429 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Words that cannot be used as TypeScript identifiers (variables, functions and type names)
+var reservedWords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+	"yield": true, "let": true, "static": true, "implements": true, "interface": true,
+	"package": true, "private": true, "protected": true, "public": true, "await": true,
+	"arguments": true, "eval": true,
+}
+
+// Predefined type names that cannot name a generated type
+var reservedTypeNames = map[string]bool{
+	"any": true, "boolean": true, "number": true, "string": true, "symbol": true, "bigint": true,
+	"never": true, "unknown": true, "object": true, "undefined": true,
+}
+
+// Local variables of the generated operation functions that path parameters must not shadow
+var operationLocals = map[string]bool{
+	"client": true, "query": true, "data": true, "config": true, "url": true, "pathParams": true,
+	"requestConfig": true, "response": true, "validated": true,
+}
+
+// Names exported by types/common.types.ts (keep in sync with the template); spec schemas with the
+// same name take precedence in the types index
+var commonTypeNames = []string{
+	"ApiResponse", "PaginatedResponse", "ErrorResponse", "ValidationError", "ValidationErrorResponse",
+	"BaseQueryParams", "FileUploadResponse", "FileUploadRequest", "ID", "Timestamp", "Email", "URL",
+	"UUID", "HttpMethod", "Partial", "Required", "Pick", "Omit", "DateRange", "AuditFields",
+}
+
+var commonEnumNames = []string{"Status", "Priority"}
+
+// Convert arbitrary text to a camelCase identifier, keeping the casing of the first word
+// (e.g. "pets.list" -> "petsList", "get-user/{id}" -> "getUserId")
+func toIdentifier(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !isIdentifierRune(r)
+	})
+
+	var b strings.Builder
+	for i, word := range words {
+		if i == 0 {
+			b.WriteString(word)
+		} else {
+			b.WriteString(toTitleCase(word))
+		}
+	}
+
+	result := b.String()
+	if result == "" {
+		return "_"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		result = "_" + result
+	}
+	return result
+}
+
+// Convert a wire name to a camelCase identifier, also splitting on underscores
+// (e.g. "created_at" -> "createdAt", "content-type" -> "contentType")
+func toCamelIdentifier(s string) string {
+	return toIdentifier(strings.Map(func(r rune) rune {
+		if r == '_' {
+			return '-'
+		}
+		return r
+	}, s))
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Suffix a name until it is not taken, e.g. "Pet" -> "Pet2"
+func uniqueName(name string, taken func(string) bool) string {
+	if !taken(name) {
+		return name
+	}
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s%d", name, i); !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// Get the identifier used for a path parameter in the generated function signature
+func pathParamIdentifier(name string) string {
+	ident := toIdentifier(name)
+	if reservedWords[ident] || operationLocals[ident] {
+		ident += "_"
+	}
+	return ident
+}
+
+// Rename schemas and operations to valid, unique TypeScript identifiers, rewriting references.
+// Names are resolved in sorted order so the result does not depend on map iteration. Returns a
+// description of every rename.
+func normalizeNames(spec *OpenAPISpec) []string {
+	var renames []string
+
+	// Schemas: names that are already valid are kept; the others are sanitized, moved off reserved
+	// names and then made unique
+	schemaRenames := make(map[string]string)
+	taken := make(map[string]bool)
+	var invalid []string
+	for _, name := range sortedKeys(getAllSchemas(spec)) {
+		if sanitizeTypeName(name) == name && !reservedWords[name] && !reservedTypeNames[name] {
+			taken[name] = true
+		} else {
+			invalid = append(invalid, name)
+		}
+	}
+	for _, name := range invalid {
+		candidate := sanitizeTypeName(name)
+		if reservedWords[candidate] || reservedTypeNames[candidate] {
+			candidate += "_"
+		}
+		candidate = uniqueName(candidate, func(n string) bool { return taken[n] })
+		taken[candidate] = true
+		schemaRenames[name] = candidate
+		// Dots becoming underscores (package-qualified names) is expected and not reported
+		if candidate != strings.ReplaceAll(name, ".", "_") {
+			renames = append(renames, fmt.Sprintf("schema %q -> %s", name, candidate))
+		}
+	}
+	if len(schemaRenames) > 0 {
+		renameSchemas(spec, schemaRenames)
+	}
+
+	// Operations: valid identifiers whose operation file names (kebab-case) are unique
+	takenFiles := make(map[string]bool)
+	for _, path := range sortedPaths(spec) {
+		for _, method := range spec.Paths[path].operations() {
+			original := method.op.OperationID
+			candidate := original
+			if candidate == "" {
+				candidate = generateOperationID(method.name, path)
+			}
+			candidate = toIdentifier(candidate)
+			if reservedWords[candidate] {
+				candidate += "_"
+			}
+			candidate = uniqueName(candidate, func(n string) bool { return takenFiles[toKebabCase(n)] })
+			takenFiles[toKebabCase(candidate)] = true
+			if original != "" && candidate != original {
+				renames = append(renames, fmt.Sprintf("operation %q -> %s", original, candidate))
+			}
+			method.op.OperationID = candidate
+		}
+	}
+
+	return renames
+}
+
+// Rekey component schemas and rewrite every reference to a renamed schema
+func renameSchemas(spec *OpenAPISpec, schemaRenames map[string]string) {
+	for _, schemas := range []map[string]*Schema{spec.Components.Schemas, spec.Definitions} {
+		renamed := make(map[string]*Schema, len(schemas))
+		for name, schema := range schemas {
+			if newName, ok := schemaRenames[name]; ok {
+				renamed[newName] = schema
+			} else {
+				renamed[name] = schema
+			}
+		}
+		for name := range schemas {
+			delete(schemas, name)
+		}
+		for name, schema := range renamed {
+			schemas[name] = schema
+		}
+	}
+
+	rewrite := func(s *Schema) {
+		if s.Ref == "" {
+			return
+		}
+		idx := strings.LastIndex(s.Ref, "/")
+		name := strings.NewReplacer("~1", "/", "~0", "~").Replace(s.Ref[idx+1:])
+		if newName, ok := schemaRenames[name]; ok {
+			s.Ref = s.Ref[:idx+1] + newName
+		}
+	}
+	for _, schema := range getAllSchemas(spec) {
+		walkSchema(schema, rewrite)
+	}
+	for _, pathItem := range spec.Paths {
+		for _, method := range pathItem.operations() {
+			for _, param := range method.op.Parameters {
+				walkSchema(param.Schema, rewrite)
+			}
+			if method.op.RequestBody != nil {
+				for _, content := range method.op.RequestBody.Content {
+					walkSchema(content.Schema, rewrite)
+				}
+			}
+			for _, response := range method.op.Responses {
+				for _, content := range response.Content {
+					walkSchema(content.Schema, rewrite)
+				}
+				walkSchema(response.Schema, rewrite)
+			}
+		}
+	}
+}
+
+func sortedPaths(spec *OpenAPISpec) []string {
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Assign every declaration used by the resources to exactly one of them, so each type, zod schema
+// and validator is declared in a single file. The first resource in alphabetical order wins.
+func schemaOwners(api *ir.API) map[string]string {
+	resources := append([]*ir.Resource{}, api.Resources...)
+	sort.Slice(resources, func(i, j int) bool {
+		return strings.ToLower(resources[i].Name) < strings.ToLower(resources[j].Name)
+	})
+
+	owners := make(map[string]string)
+	for _, resource := range resources {
+		for name := range collectResourceDecls(api, resource) {
+			if _, ok := owners[name]; !ok {
+				owners[name] = resource.Name
+			}
+		}
+	}
+	return owners
+}
+
+// Get the declarations made in a resource's files
+func ownedResourceDecls(api *ir.API, owners map[string]string, resource *ir.Resource) map[string]*ir.Decl {
+	decls := make(map[string]*ir.Decl)
+	for name, decl := range collectResourceDecls(api, resource) {
+		if owners[name] == resource.Name {
+			decls[name] = decl
+		}
+	}
+	return decls
+}
+
+// SchemaImport lists the names a resource file imports from another resource's file
+type SchemaImport struct {
+	Resource string
+	Names    []string
+}
+
+// Find the declarations referenced by a resource's declarations that are made in other resources' files
+func foreignSchemaImports(decls map[string]*ir.Decl, owners map[string]string, resourceName string) []SchemaImport {
+	byResource := make(map[string]map[string]bool)
+	for _, decl := range decls {
+		for _, ref := range typeRefs(decl.Type) {
+			owner, ok := owners[ref]
+			if !ok || owner == resourceName {
+				continue
+			}
+			if byResource[owner] == nil {
+				byResource[owner] = make(map[string]bool)
+			}
+			byResource[owner][ref] = true
+		}
+	}
+
+	imports := make([]SchemaImport, 0, len(byResource))
+	for owner, refs := range byResource {
+		names := make([]string, 0, len(refs))
+		for name := range refs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		imports = append(imports, SchemaImport{Resource: strings.ToLower(owner), Names: names})
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Resource < imports[j].Resource
+	})
+	return imports
+}
+
+// Build the export statements for common.types.ts, leaving out names declared by the spec or as
+// branded scalars
+func commonTypesExports(api *ir.API, scalars map[string]bool) string {
+	shadowed := make(map[string]bool)
+	for _, decl := range api.Types {
+		shadowed[decl.Name] = true
+	}
+
+	collides := false
+	for _, name := range append(append([]string{}, commonTypeNames...), commonEnumNames...) {
+		collides = collides || shadowed[name]
+	}
+	if !collides {
+		return "export * from './common.types';\n"
+	}
+
+	keep := func(names []string) []string {
+		var kept []string
+		for _, name := range names {
+			if !shadowed[name] && !scalars[name] {
+				kept = append(kept, name)
+			}
+		}
+		return kept
+	}
+	types, enums := keep(commonTypeNames), keep(commonEnumNames)
+
+	exports := fmt.Sprintf("export type { %s } from './common.types';\n", strings.Join(types, ", "))
+	if len(enums) > 0 {
+		exports += fmt.Sprintf("export { %s } from './common.types';\n", strings.Join(enums, ", "))
+	}
+	return exports
+}
+
+// Property naming modes selected with -property-naming
+const (
+	PropertyNamingOriginal = "original"
+	PropertyNamingCamel    = "camel"
+)
+
+// ValidatePropertyNaming checks a -property-naming value
+func ValidatePropertyNaming(mode string) error {
+	switch mode {
+	case "", PropertyNamingOriginal, PropertyNamingCamel:
+		return nil
+	}
+	return fmt.Errorf("unknown property naming %q (expected original or camel)", mode)
+}
+
+// Get the TypeScript names of a set of wire names (object properties or query parameters). Wire
+// names are kept unless camelCase naming is enabled; converted names never take a name that is
+// already used as-is, and collisions get a numeric suffix.
+func propertyNames(wireNames []string, config Config) map[string]string {
+	names := make(map[string]string, len(wireNames))
+	if config.PropertyNaming != PropertyNamingCamel {
+		for _, wire := range wireNames {
+			names[wire] = wire
+		}
+		return names
+	}
+
+	sorted := append([]string{}, wireNames...)
+	sort.Strings(sorted)
+	taken := make(map[string]bool)
+	var converted []string
+	for _, wire := range sorted {
+		if toCamelIdentifier(wire) == wire {
+			names[wire] = wire
+			taken[wire] = true
+		} else {
+			converted = append(converted, wire)
+		}
+	}
+	for _, wire := range converted {
+		name := uniqueName(toCamelIdentifier(wire), func(n string) bool { return taken[n] })
+		names[wire] = name
+		taken[name] = true
+	}
+	return names
+}
+
+// Get the identifiers of a set of wire names in a target language, keyed by wire name. Wire names
+// that convert to themselves are resolved first, then those only made of letters, digits and
+// underscores, so a numeric suffix goes to the names that lost characters in the conversion
+// ("@id" becomes ID2 rather than "id").
+func memberNames(wireNames []string, convert func(string) string, taken map[string]bool) map[string]string {
+	rank := func(wire string) int {
+		if convert(wire) == wire {
+			return 0
+		}
+		for _, r := range wire {
+			if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return 2
+			}
+		}
+		return 1
+	}
+	ordered := append([]string{}, wireNames...)
+	sort.SliceStable(ordered, func(i, j int) bool { return rank(ordered[i]) < rank(ordered[j]) })
+
+	names := make(map[string]string, len(wireNames))
+	for _, wire := range ordered {
+		name := uniqueName(convert(wire), func(n string) bool { return taken[n] })
+		names[wire] = name
+		taken[name] = true
+	}
+	return names
+}
+
+// Get the TypeScript names of an object's fields, keyed by wire name
+func fieldNames(fields []*ir.Field, config Config) map[string]string {
+	wireNames := make([]string, 0, len(fields))
+	for _, field := range fields {
+		wireNames = append(wireNames, field.Name)
+	}
+	return propertyNames(wireNames, config)
+}
+
+// Report whether a type (without following references) has fields renamed on the wire
+func hasRenamedFields(t *ir.Type, config Config) bool {
+	if config.PropertyNaming != PropertyNamingCamel {
+		return false
+	}
+	found := false
+	ir.Walk(t, func(t *ir.Type) {
+		for wire, name := range fieldNames(t.Fields, config) {
+			if wire != name {
+				found = true
+			}
+		}
+	})
+	return found
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeSchemaNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemas []string
+		want    map[string]string
+		renames []string
+	}{
+		{
+			name:    "valid names keep priority over sanitized ones",
+			schemas: []string{"A-B", "A_B"},
+			want:    map[string]string{"A-B": "A_B2", "A_B": "A_B"},
+			renames: []string{`schema "A-B" -> A_B2`},
+		},
+		{
+			name:    "dots become underscores without a rename",
+			schemas: []string{"pkg.Response", "fiber.Map"},
+			want:    map[string]string{"pkg.Response": "pkg_Response", "fiber.Map": "fiber_Map"},
+		},
+		{
+			name:    "dotted names still report a collision",
+			schemas: []string{"pkg.Response", "pkg_Response"},
+			want:    map[string]string{"pkg.Response": "pkg_Response2", "pkg_Response": "pkg_Response"},
+			renames: []string{`schema "pkg.Response" -> pkg_Response2`},
+		},
+		{
+			name:    "reserved words and leading digits are fixed",
+			schemas: []string{"delete", "1st"},
+			want:    map[string]string{"delete": "delete_", "1st": "_1st"},
+			renames: []string{`schema "1st" -> _1st`, `schema "delete" -> delete_`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The description records the original name of each schema
+			spec := &OpenAPISpec{Components: Components{Schemas: make(map[string]*Schema)}}
+			for _, name := range tt.schemas {
+				spec.Components.Schemas[name] = &Schema{Description: name}
+			}
+
+			renames := normalizeNames(spec)
+
+			got := make(map[string]string)
+			for newName, schema := range spec.Components.Schemas {
+				got[schema.Description] = newName
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("names = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(renames, tt.renames) {
+				t.Errorf("renames = %q, want %q", renames, tt.renames)
+			}
+		})
+	}
+}
+
+func TestMemberNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func(string) string
+		wire    []string
+		want    map[string]string
+	}{
+		{
+			name:    "go fields",
+			convert: goName,
+			wire:    []string{"@id", "created-at", "created_at", "id"},
+			want:    map[string]string{"@id": "ID2", "created-at": "CreatedAt2", "created_at": "CreatedAt", "id": "ID"},
+		},
+		{
+			name:    "python fields",
+			convert: pyFieldName,
+			wire:    []string{"@id", "id", "userId", "user_id"},
+			want:    map[string]string{"@id": "id2", "id": "id", "userId": "user_id2", "user_id": "user_id"},
+		},
+		{
+			name:    "dart fields",
+			convert: dartMemberName,
+			wire:    []string{"@id", "id", "user_id", "userId"},
+			want:    map[string]string{"@id": "id2", "id": "id", "user_id": "userId2", "userId": "userId"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := memberNames(tt.wire, tt.convert, make(map[string]bool))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("memberNames(%q) = %v, want %v", tt.wire, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Reject spec names that become file or directory names and try to leave the output directory
+// (e.g. a tag of "../../etc"), reporting where in the spec they come from
+func checkOutputNames(spec *OpenAPISpec) error {
+	for _, p := range sortedPaths(spec) {
+		for _, method := range spec.Paths[p].operations() {
+			check := func(name string, field ...string) error {
+				if !isTraversalName(name) {
+					return nil
+				}
+				location := specPointer(append([]string{"paths", p, strings.ToLower(method.name)}, field...)...)
+				return fmt.Errorf("unsafe name %q at %s: names used for output paths must not contain \"..\" segments", name, location)
+			}
+
+			if err := check(method.op.OperationID, "operationId"); err != nil {
+				return err
+			}
+			if err := check(method.op.XSvegerResource, "x-sveger-resource"); err != nil {
+				return err
+			}
+			for i, tag := range method.op.Tags {
+				if err := check(tag, "tags", fmt.Sprint(i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Report whether a name contains a ".." path segment or a NUL byte
+func isTraversalName(name string) bool {
+	if strings.ContainsRune(name, 0) {
+		return true
+	}
+	for _, segment := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if strings.TrimSpace(segment) == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// Build a JSON pointer (RFC 6901) to a location in the spec, e.g. #/paths/~1pets/get
+func specPointer(tokens ...string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	var b strings.Builder
+	b.WriteString("#")
+	for _, token := range tokens {
+		b.WriteString("/" + escaper.Replace(token))
+	}
+	return b.String()
+}
+
+// Join generated file name segments onto the output root, failing if a segment is not a plain
+// file name or the result would leave the root
+func outputPath(root string, segments ...string) (string, error) {
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, "/\\\x00") {
+			return "", fmt.Errorf("unsafe output path segment %q", segment)
+		}
+	}
+
+	result := filepath.Join(append([]string{root}, segments...)...)
+	rel, err := filepath.Rel(root, result)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("output path %s is outside %s", result, root)
+	}
+	return result, nil
+}
+
+// Sanitize a name for use as a file name segment, replacing characters that are reserved on
+// common file systems
+func fileSegment(name string) string {
+	segment := strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	segment = strings.Trim(segment, ". ")
+	if segment == "" {
+		return "_"
+	}
+	return segment
+}
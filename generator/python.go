@@ -0,0 +1,780 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for the Python client
+type PyClientTemplateData struct {
+	DefaultBaseURL string
+	Timeout        string
+	Resources      []PyResourceDef
+}
+
+type PyModelsTemplateData struct {
+	Names   []string
+	Classes []PyClass
+	Aliases []PyAlias
+}
+
+type PyResourceTemplateData struct {
+	Resource string
+	Class    string
+	Imports  []string
+	Methods  []PyMethod
+	// Variants renders the class once synchronous and once asynchronous
+	Variants []bool
+}
+
+type PyResourceDef struct {
+	// Class is the resource class (Async<Class> for the async client), Attribute the client
+	// attribute exposing it and Module its file in resources/
+	Class     string
+	Attribute string
+	Module    string
+}
+
+// PyClass is a dataclass (Fields) or an Enum (Enum, Base, Members)
+type PyClass struct {
+	Name    string
+	Doc     string
+	Enum    bool
+	Base    string
+	Fields  []PyField
+	Members []PyMember
+}
+
+type PyField struct {
+	Comment string
+	Name    string
+	Type    string
+	// Default is the rest of the declaration: "", " = None" or " = field(...)"
+	Default string
+}
+
+type PyMember struct {
+	Name  string
+	Value string
+}
+
+// PyAlias is a type alias (unions, literal enums, lists, maps)
+type PyAlias struct {
+	Comment string
+	Name    string
+	Type    string
+}
+
+type PyMethod struct {
+	Name       string
+	Doc        string
+	Params     string
+	ReturnType string
+	// Args are the arguments of the client's _request call
+	Args []string
+}
+
+// pyModel maps the API's types to Python types
+type pyModel struct {
+	config Config
+	decls  map[string]*ir.Decl
+	// names maps declaration names to Python names; classes and aliases hold the Python
+	// declarations, including the classes hoisted from inline objects
+	names   map[string]string
+	classes map[string]PyClass
+	aliases map[string]PyAlias
+	// isAlias marks declarations emitted as aliases, which are defined after every class
+	isAlias map[string]bool
+	taken   map[string]bool
+	// refs collects the names a resource module refers to, for its imports
+	refs map[string]bool
+}
+
+var pyKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true,
+	"await": true, "break": true, "class": true, "continue": true, "def": true, "del": true,
+	"elif": true, "else": true, "except": true, "finally": true, "for": true, "from": true,
+	"global": true, "if": true, "import": true, "in": true, "is": true, "lambda": true,
+	"nonlocal": true, "not": true, "or": true, "pass": true, "raise": true, "return": true,
+	"try": true, "while": true, "with": true, "yield": true,
+}
+
+// Names models.py imports or that would shadow builtins used by the generated code
+var pyReservedTypeNames = []string{
+	"Any", "Dict", "List", "Literal", "Optional", "Union", "Enum", "dataclass", "field", "date",
+	"datetime", "annotations", "str", "int", "float", "bool", "bytes", "dict", "list", "object",
+	"type", "ApiError", "Client", "AsyncClient", "DEFAULT_BASE_URL", "UNSET",
+}
+
+// GeneratePython generates a Python client package from the spec
+func GeneratePython(config Config) (*Report, error) {
+	api, report, err := prepareSpec(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(config.OutputPath, "resources"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := generatePythonClient(api, config); err != nil {
+		return nil, err
+	}
+
+	report.Deprecated = collectDeprecations(api)
+	return report, nil
+}
+
+func generatePythonClient(api *ir.API, config Config) error {
+	model := newPyModel(api, config)
+
+	resourceTmpl, err := loadTemplate("python/resource.tmpl")
+	if err != nil {
+		return err
+	}
+
+	var resources []PyResourceDef
+	modules := make(map[string]bool)
+	for i, class := range goResourceNames(api) {
+		resource := api.Resources[i]
+		// Module names are lower case, so they are unique like the Go resource names
+		module := uniqueName(pySnakeName(resource.Name), func(n string) bool { return modules[n] })
+		modules[module] = true
+		def := PyResourceDef{Class: class + "Resource", Attribute: module, Module: module}
+		resources = append(resources, def)
+
+		data := PyResourceTemplateData{Resource: resource.Name, Class: def.Class, Variants: []bool{false, true}}
+		model.refs = make(map[string]bool)
+		methodNames := make(map[string]bool)
+		for _, op := range resource.Operations {
+			name := uniqueName(pySnakeName(op.ID), func(n string) bool { return methodNames[n] })
+			methodNames[name] = true
+			method, err := model.method(name, op)
+			if err != nil {
+				return err
+			}
+			data.Methods = append(data.Methods, method)
+		}
+		data.Imports = pyResourceImports(data.Methods, model.refs)
+
+		filePath, err := outputPath(filepath.Join(config.OutputPath, "resources"), module+".py")
+		if err != nil {
+			return err
+		}
+		if err := writePyFile(resourceTmpl, data, filePath); err != nil {
+			return err
+		}
+	}
+
+	// Types are collected while generating the resources, which can hoist inline schemas
+	for _, decl := range api.Types {
+		model.declare(decl)
+	}
+	modelsData := PyModelsTemplateData{}
+	for _, name := range sortedKeys(model.classes) {
+		modelsData.Classes = append(modelsData.Classes, model.classes[name])
+		modelsData.Names = append(modelsData.Names, name)
+	}
+	for _, name := range sortedKeys(model.aliases) {
+		modelsData.Aliases = append(modelsData.Aliases, model.aliases[name])
+		modelsData.Names = append(modelsData.Names, name)
+	}
+	sort.Strings(modelsData.Names)
+
+	timeout := 10.0
+	if ms, err := strconv.ParseFloat(config.Timeout, 64); err == nil && ms > 0 {
+		timeout = ms / 1000
+	}
+	files := map[string]any{
+		"models.tmpl":  modelsData,
+		"runtime.tmpl": nil,
+		"client.tmpl": PyClientTemplateData{
+			DefaultBaseURL: strconv.Quote(defaultBaseURL(api, config)),
+			Timeout:        strconv.FormatFloat(timeout, 'f', -1, 64),
+			Resources:      resources,
+		},
+		"init.tmpl": struct{ Title string }{pyDocText(api.Title)},
+	}
+	fileNames := map[string]string{
+		"models.tmpl":  "models.py",
+		"runtime.tmpl": "_runtime.py",
+		"client.tmpl":  "client.py",
+		"init.tmpl":    "__init__.py",
+	}
+	for _, name := range sortedKeys(files) {
+		tmpl, err := loadTemplate("python/" + name)
+		if err != nil {
+			return err
+		}
+		if err := writePyFile(tmpl, files[name], filepath.Join(config.OutputPath, fileNames[name])); err != nil {
+			return err
+		}
+	}
+	if err := writeFile(filepath.Join(config.OutputPath, "resources", "__init__.py"), "# Code generated by sveger. DO NOT EDIT.\n"); err != nil {
+		return err
+	}
+	// PEP 561 marker: the package ships type hints
+	return writeFile(filepath.Join(config.OutputPath, "py.typed"), "")
+}
+
+// Render a Python template, collapsing the blank lines left by empty sections to at most two
+func writePyFile(tmpl *template.Template, data any, path string) error {
+	content, err := executeTemplate(tmpl, data)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, pyBlankLines.ReplaceAllString(content, "\n\n\n"))
+}
+
+var pyBlankLines = regexp.MustCompile(`\n{4,}`)
+
+func newPyModel(api *ir.API, config Config) *pyModel {
+	m := &pyModel{
+		config:  config,
+		decls:   declsByName(api),
+		names:   make(map[string]string),
+		classes: make(map[string]PyClass),
+		aliases: make(map[string]PyAlias),
+		isAlias: make(map[string]bool),
+		taken:   make(map[string]bool),
+		refs:    make(map[string]bool),
+	}
+	for _, name := range pyReservedTypeNames {
+		m.taken[name] = true
+	}
+	for _, decl := range api.Types {
+		m.names[decl.Name] = m.reserve(pyTypeName(decl.Name))
+		m.isAlias[decl.Name] = !m.isClassType(decl.Type)
+	}
+	return m
+}
+
+// Convert a declaration name, a TypeScript identifier, to a Python one
+func pyTypeName(name string) string {
+	name = strings.ReplaceAll(name, "$", "_")
+	if name == "" {
+		return "Model"
+	}
+	if pyKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// Take a module-level name, suffixed until it is unique
+func (m *pyModel) reserve(name string) string {
+	name = uniqueName(name, func(n string) bool { return m.taken[n] })
+	m.taken[name] = true
+	return name
+}
+
+// Report whether a type is declared as a class: an object, an allOf of objects, or a string or
+// integer enum
+func (m *pyModel) isClassType(t *ir.Type) bool {
+	if len(t.Enum) > 0 {
+		return pyEnumBase(t) != "" && len(pyEnumMembers(t)) > 0
+	}
+	switch t.Kind {
+	case ir.Object:
+		return true
+	case ir.Intersection:
+		_, ok := flattenFields(t, m.decls, make(map[string]bool))
+		return ok
+	}
+	return false
+}
+
+func (m *pyModel) declare(decl *ir.Decl) {
+	name := m.names[decl.Name]
+	t := decl.Type
+	switch {
+	case m.isAlias[decl.Name]:
+		m.aliases[name] = PyAlias{Comment: pyComment(t.Description, ""), Name: name, Type: m.pyType(name, "", t, true)}
+	case len(t.Enum) > 0:
+		m.classes[name] = PyClass{
+			Name:    name,
+			Doc:     pyDocstring(t.Description, "    "),
+			Enum:    true,
+			Base:    pyEnumBase(t),
+			Members: pyEnumMembers(t),
+		}
+	default:
+		fields, _ := flattenFields(t, m.decls, map[string]bool{decl.Name: true})
+		m.classes[name] = m.dataclass(name, t.Description, fields)
+	}
+}
+
+// Build a dataclass. Required fields come first, as dataclasses require; optional fields default
+// to None, and fields renamed from their wire names record them in their metadata.
+func (m *pyModel) dataclass(name, description string, fields []*ir.Field) PyClass {
+	class := PyClass{Name: name, Doc: pyDocstring(description, "    ")}
+	wireNames := make([]string, 0, len(fields))
+	for _, f := range fields {
+		wireNames = append(wireNames, f.Name)
+	}
+	names := memberNames(wireNames, pyFieldName, make(map[string]bool))
+	var required, optional []PyField
+	for _, f := range fields {
+		fieldName := names[f.Name]
+		fieldType := m.pyType(name, f.Name, f.Type, false)
+		comment := f.Type.Description
+		if f.Type.Deprecated {
+			comment = strings.TrimSpace(comment + "\n\nDeprecated: deprecated in the API specification.")
+		}
+		field := PyField{Comment: pyComment(comment, "    "), Name: fieldName, Type: fieldType}
+		metadata := ""
+		if fieldName != f.Name {
+			metadata = fmt.Sprintf(`metadata={"wire": %s}`, strconv.Quote(f.Name))
+		}
+		if f.Required {
+			if metadata != "" {
+				field.Default = " = field(" + metadata + ")"
+			}
+			required = append(required, field)
+			continue
+		}
+		field.Type = pyOptional(fieldType)
+		field.Default = " = None"
+		if metadata != "" {
+			field.Default = " = field(default=None, " + metadata + ")"
+		}
+		optional = append(optional, field)
+	}
+	class.Fields = append(required, optional...)
+	return class
+}
+
+// Get the Python type of a type. Inline objects are hoisted into dataclasses named after their
+// owner and field. Inside aliases, which are defined after the classes, references to other
+// aliases are quoted.
+func (m *pyModel) pyType(owner, field string, t *ir.Type, inAlias bool) string {
+	if t == nil {
+		return "Any"
+	}
+	base := m.pyBaseType(owner, field, t, inAlias)
+	if t.Nullable {
+		return pyOptional(base)
+	}
+	return base
+}
+
+func (m *pyModel) pyBaseType(owner, field string, t *ir.Type, inAlias bool) string {
+	if t.Kind == ir.Ref {
+		return m.refName(t.Ref, inAlias)
+	}
+	if len(t.Enum) > 0 {
+		var literals []string
+		for _, value := range t.Enum {
+			if literal := pyLiteral(value); literal != "" {
+				literals = append(literals, literal)
+			}
+		}
+		if len(literals) > 0 {
+			return "Literal[" + strings.Join(literals, ", ") + "]"
+		}
+	}
+
+	switch t.Kind {
+	case ir.String:
+		switch t.Format {
+		case "date-time":
+			return "datetime"
+		case "date":
+			return "date"
+		}
+		return "str"
+	case ir.Integer:
+		return "int"
+	case ir.Number:
+		return "float"
+	case ir.Boolean:
+		return "bool"
+	case ir.Array:
+		return "List[" + m.pyType(owner, field+"Item", t.Items, inAlias) + "]"
+	case ir.Map:
+		return "Dict[str, " + m.pyType(owner, field+"Value", t.Values, inAlias) + "]"
+	case ir.Union:
+		var variants []string
+		for _, variant := range t.Variants {
+			if variantType := m.pyType(owner, field, variant, inAlias); !contains(variants, variantType) {
+				variants = append(variants, variantType)
+			}
+		}
+		if contains(variants, "Any") {
+			return "Any"
+		}
+		if len(variants) == 1 {
+			return variants[0]
+		}
+		return "Union[" + strings.Join(variants, ", ") + "]"
+	case ir.Intersection:
+		if len(t.Variants) == 1 {
+			return m.pyType(owner, field, t.Variants[0], inAlias)
+		}
+		fields, ok := flattenFields(t, m.decls, make(map[string]bool))
+		if !ok {
+			return "Dict[str, Any]"
+		}
+		return m.hoist(owner, field, t.Description, fields)
+	case ir.Object:
+		return m.hoist(owner, field, t.Description, t.Fields)
+	}
+	return "Any"
+}
+
+// Declare an inline object as a dataclass
+func (m *pyModel) hoist(owner, field, description string, fields []*ir.Field) string {
+	name := m.reserve(owner + goName(field))
+	m.refs[name] = true
+	m.classes[name] = m.dataclass(name, description, fields)
+	return name
+}
+
+func (m *pyModel) refName(name string, inAlias bool) string {
+	pyName, ok := m.names[name]
+	if !ok {
+		return "Any"
+	}
+	m.refs[pyName] = true
+	if inAlias && m.isAlias[name] {
+		return strconv.Quote(pyName)
+	}
+	return pyName
+}
+
+// Generate a resource method for an operation. Path parameters are positional; query and header
+// parameters, form fields and the body are keyword-only, optional ones defaulting to None.
+func (m *pyModel) method(name string, op *ir.Operation) (PyMethod, error) {
+	owner := goName(op.ID)
+	taken := map[string]bool{"self": true, "body": true}
+	pathArgs := make(map[string]string)
+	positional := []string{"self"}
+	var required, optional, query, headers []string
+
+	for _, param := range op.Params {
+		if param.In != "path" && param.In != "query" && param.In != "header" {
+			continue
+		}
+		argName := uniqueName(pySnakeName(param.Name), func(n string) bool { return taken[n] })
+		taken[argName] = true
+		argType := m.pyType(owner, goName(param.Name), param.Type, false)
+		entry := strconv.Quote(param.Name) + ": " + argName
+		switch {
+		case param.In == "path":
+			pathArgs[param.Name] = argName
+			positional = append(positional, argName+": "+argType)
+			continue
+		case param.In == "query":
+			query = append(query, entry)
+		default:
+			headers = append(headers, entry)
+		}
+		if param.Required {
+			required = append(required, argName+": "+argType)
+		} else {
+			optional = append(optional, argName+": "+pyOptional(argType)+" = None")
+		}
+	}
+
+	args := []string{strconv.Quote(strings.ToUpper(op.Method)), pyPathExpression(op.Path, pathArgs)}
+	if len(query) > 0 {
+		args = append(args, "params={"+strings.Join(query, ", ")+"}")
+	}
+	if len(headers) > 0 {
+		args = append(args, "headers={"+strings.Join(headers, ", ")+"}")
+	}
+	fields, multipart, isForm, err := formFields(op, m.decls)
+	if err != nil {
+		return PyMethod{}, err
+	}
+	if isForm {
+		var form []string
+		for _, field := range fields {
+			argName := uniqueName(pySnakeName(field.Name), func(n string) bool { return taken[n] })
+			taken[argName] = true
+			argType := m.pyType(owner, goName(field.Name), field.Type, false)
+			if isFileType(field.Type) {
+				argType = "FileContent"
+				if field.Type.Kind == ir.Array {
+					argType = "List[FileContent]"
+				}
+			}
+			form = append(form, strconv.Quote(field.Name)+": "+argName)
+			if field.Required {
+				required = append(required, argName+": "+argType)
+			} else {
+				optional = append(optional, argName+": "+pyOptional(argType)+" = None")
+			}
+		}
+		args = append(args, "form={"+strings.Join(form, ", ")+"}")
+		if multipart {
+			args = append(args, "multipart=True")
+		}
+	}
+	// Other bodies are only sent as JSON
+	if !isForm && op.Body != nil && op.Body.IsJSON() && op.Body.Type != nil {
+		bodyType := m.pyType(owner, "Body", op.Body.Type, false)
+		if op.Body.Required {
+			required = append([]string{"body: " + bodyType}, required...)
+			args = append(args, "body=body")
+		} else {
+			optional = append([]string{"body: " + pyOptional(bodyType) + " = None"}, optional...)
+			args = append(args, "body=UNSET if body is None else body")
+		}
+	}
+
+	returnType := "None"
+	if response := op.SuccessResponse(); response != nil {
+		returnType = m.pyType(owner, "Response", response.Type, false)
+		args = append(args, "response_type="+returnType)
+	}
+
+	params := positional
+	if keywords := append(required, optional...); len(keywords) > 0 {
+		params = append(append(params, "*"), keywords...)
+	}
+	paramList := strings.Join(params, ", ")
+	if len(paramList)+len(name)+len(returnType) > 80 {
+		paramList = "\n        " + strings.Join(params, ",\n        ") + ",\n    "
+	}
+
+	text := operationDocText(op)
+	if op.Deprecated {
+		text = strings.TrimSpace(text + "\n\nDeprecated: deprecated in the API specification.")
+	}
+	return PyMethod{
+		Name:       name,
+		Doc:        pyDocstring(text, "        "),
+		Params:     paramList,
+		ReturnType: returnType,
+		Args:       args,
+	}, nil
+}
+
+// Make a type accept None
+func pyOptional(pyType string) string {
+	if pyType == "Any" || strings.HasPrefix(pyType, "Optional[") {
+		return pyType
+	}
+	return "Optional[" + pyType + "]"
+}
+
+func pyEnumBase(t *ir.Type) string {
+	switch t.Kind {
+	case ir.String:
+		return "str"
+	case ir.Integer:
+		return "int"
+	}
+	return ""
+}
+
+// Get the members of a string or integer enum, named by x-enum-varnames or after their values
+func pyEnumMembers(t *ir.Type) []PyMember {
+	var members []PyMember
+	taken := make(map[string]bool)
+	for i, value := range t.Enum {
+		literal := pyLiteral(value)
+		if literal == "" {
+			continue
+		}
+		if _, ok := value.(string); !ok && t.Kind == ir.String {
+			literal = strconv.Quote(fmt.Sprint(value))
+		}
+		label := fmt.Sprint(value)
+		if i < len(t.EnumNames) {
+			label = t.EnumNames[i]
+		}
+		name := pyConstName(label)
+		name = uniqueName(name, func(n string) bool { return taken[n] })
+		taken[name] = true
+		members = append(members, PyMember{Name: name, Value: literal})
+	}
+	return members
+}
+
+// Render a scalar spec value as a Python literal, or "" for null and other values
+func pyLiteral(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// Convert a spec name to a snake_case Python identifier (petId -> pet_id)
+func pySnakeName(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	name := strings.Join(words, "_")
+	if name == "" {
+		return "value"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "v_" + name
+	}
+	if pyKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// Convert a property name to a dataclass field name. Fields named like the module's field()
+// function would shadow it for the fields after them.
+func pyFieldName(s string) string {
+	name := pySnakeName(s)
+	if name == "field" || name == "self" {
+		name += "_"
+	}
+	return name
+}
+
+// Convert a name to an UPPER_SNAKE_CASE enum member name
+func pyConstName(s string) string {
+	name := strings.ToUpper(pySnakeName(s))
+	if strings.HasSuffix(name, "_") && pyKeywords[strings.TrimSuffix(strings.ToLower(name), "_")] {
+		// Keywords are lower case, so the upper case name is already valid
+		name = strings.TrimSuffix(name, "_")
+	}
+	return name
+}
+
+// Build the Python expression for a request path, escaping path parameters
+func pyPathExpression(p string, pathArgs map[string]string) string {
+	var parts []string
+	rest := p
+	for {
+		start := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if start < 0 || end < start {
+			break
+		}
+		if start > 0 {
+			parts = append(parts, strconv.Quote(rest[:start]))
+		}
+		name := rest[start+1 : end]
+		if arg, ok := pathArgs[name]; ok {
+			parts = append(parts, "path_param("+arg+")")
+		} else {
+			parts = append(parts, strconv.Quote(rest[start:end+1]))
+		}
+		rest = rest[end+1:]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// List the import lines a resource module needs: the standard library, then the package
+func pyResourceImports(methods []PyMethod, refs map[string]bool) []string {
+	var b strings.Builder
+	for _, method := range methods {
+		b.WriteString(method.Params + " " + method.ReturnType + " " + strings.Join(method.Args, " ") + "\n")
+	}
+	code := b.String()
+
+	typing := []string{"TYPE_CHECKING"}
+	for _, name := range []string{"Any", "Dict", "List", "Literal", "Optional", "Union"} {
+		if regexp.MustCompile(`\b` + name + `\b`).MatchString(code) {
+			typing = append(typing, name)
+		}
+	}
+
+	var imports []string
+	var datetimes []string
+	for _, name := range []string{"date", "datetime"} {
+		if regexp.MustCompile(`\b` + name + `\b`).MatchString(code) {
+			datetimes = append(datetimes, name)
+		}
+	}
+	if len(datetimes) > 0 {
+		imports = append(imports, "from datetime import "+strings.Join(datetimes, ", "))
+	}
+	imports = append(imports, "from typing import "+strings.Join(typing, ", "))
+
+	var local []string
+	var runtime []string
+	if strings.Contains(code, "UNSET") {
+		runtime = append(runtime, "UNSET")
+	}
+	if strings.Contains(code, "FileContent") {
+		runtime = append(runtime, "FileContent")
+	}
+	if strings.Contains(code, "path_param(") {
+		runtime = append(runtime, "path_param")
+	}
+	if len(runtime) > 0 {
+		local = append(local, "from .._runtime import "+strings.Join(runtime, ", "))
+	}
+	if models := sortedKeys(refs); len(models) > 0 {
+		local = append(local, "from ..models import (\n    "+strings.Join(models, ",\n    ")+",\n)")
+	}
+	if len(local) > 0 {
+		imports = append(append(imports, ""), local...)
+	}
+	return imports
+}
+
+// Build a docstring at an indentation, or "" when there is nothing to say
+func pyDocstring(text, indent string) string {
+	lines := splitDocText(text)
+	if len(lines) == 0 {
+		return ""
+	}
+	for i, line := range lines {
+		lines[i] = pyDocText(line)
+	}
+	if len(lines) == 1 {
+		return indent + `"""` + lines[0] + `"""`
+	}
+	var b strings.Builder
+	b.WriteString(indent + `"""` + lines[0] + "\n")
+	for _, line := range lines[1:] {
+		if line == "" {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(indent + line + "\n")
+		}
+	}
+	b.WriteString(indent + `"""`)
+	return b.String()
+}
+
+// Escape text for a docstring: backslashes and quotes, which could end it early
+func pyDocText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// Build # comment lines at an indentation, or ""
+func pyComment(text, indent string) string {
+	lines := splitDocText(text)
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(indent+"# "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
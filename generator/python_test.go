@@ -0,0 +1,14 @@
+package generator
+
+import "testing"
+
+func TestPythonFormFields(t *testing.T) {
+	files := generateFiles(t, Config{InputPath: "petstore.json", Language: "python"}, GeneratePython)
+
+	assertContains(t, files, "resources/pet.py",
+		"from .._runtime import FileContent, path_param",
+		"        name: Optional[str] = None,\n        status: Optional[str] = None,\n",
+		`form={"name": name, "status": status},`,
+		"        file: Optional[FileContent] = None,\n",
+		`form={"additionalMetadata": additional_metadata, "file": file},`+"\n            multipart=True,")
+}
@@ -0,0 +1,44 @@
+package generator
+
+import "testing"
+
+func TestRecursiveTree(t *testing.T) {
+	files := generateTypeScriptFiles(t, "testdata/recursive/tree.yaml")
+
+	assertContains(t, files, "types/trees.types.ts",
+		"export type TreeNode = Node & { children?: TreeNode[]; parent?: TreeNode };")
+	assertContains(t, files, "types/lists.types.ts",
+		"next?: ListNode | null;")
+	assertContains(t, files, "schemas/trees.schemas.ts",
+		"export const TreeNodeSchema: z.ZodType<Types.TreeNode> = NodeSchema.and(",
+		"children: z.array(z.lazy(() => TreeNodeSchema)).optional(),",
+		"parent: z.lazy(() => TreeNodeSchema).optional(),")
+	assertContains(t, files, "schemas/lists.schemas.ts",
+		"export const ListNodeSchema: z.ZodType<Types.ListNode> = z.object({",
+		"next: z.lazy(() => ListNodeSchema).nullable().optional(),")
+	assertContains(t, files, "validators/trees.validators.ts",
+		"children: v.optional(v.array(v.lazy(() => validateTreeNode))),",
+		"parent: v.optional(v.lazy(() => validateTreeNode)),")
+	assertContains(t, files, "validators/lists.validators.ts",
+		"v.lazy(() => validateListNode)")
+}
+
+func TestRecursiveGraph(t *testing.T) {
+	files := generateTypeScriptFiles(t, "testdata/recursive/graph.yaml")
+
+	assertContains(t, files, "types/groups.types.ts",
+		"groups?: Group[];",
+		"manager?: User;",
+		"members?: User[];",
+		"parent?: Group | Organization;")
+	// Schemas declared before the ones they reference wrap those references in z.lazy
+	assertContains(t, files, "schemas/groups.schemas.ts",
+		"export const UserSchema: z.ZodType<Types.User> = z.object({",
+		"groups: z.array(z.lazy(() => GroupSchema)).optional(),",
+		"manager: z.lazy(() => UserSchema).optional(),",
+		"parent: z.union([z.lazy(() => GroupSchema), OrganizationSchema]).optional(),")
+	assertContains(t, files, "validators/groups.validators.ts",
+		"groups: v.optional(v.array(v.lazy(() => validateGroup))),",
+		"manager: v.optional(v.lazy(() => validateUser)),",
+		"parent: v.optional(v.anyOf(v.lazy(() => validateGroup), validateOrganization)),")
+}
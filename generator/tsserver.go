@@ -0,0 +1,346 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Frameworks the TypeScript route handlers are generated for with -server
+const (
+	ServerHono    = "hono"
+	ServerExpress = "express"
+)
+
+// ValidateServer checks a -server value
+func ValidateServer(server string) error {
+	switch server {
+	case "", ServerHono, ServerExpress:
+		return nil
+	}
+	return fmt.Errorf("unknown server framework %q (expected hono or express)", server)
+}
+
+// Template data for a resource's handlers and routes
+type ServerResourceData struct {
+	ResourceName      string
+	ResourceNameLower string
+	FileName          string
+	Handlers          string
+	Routes            string
+	Codecs            bool
+	Forms             bool
+	Operations        []ServerOperationDef
+}
+
+type ServerOperationDef struct {
+	Name        string
+	RequestName string
+	Doc         string
+	Method      string
+	// RoutePath is the quoted path in the :param syntax shared by Hono and Express
+	RoutePath    string
+	Status       int
+	PathParams   []ServerParamDef
+	QueryParams  []ServerParamDef
+	HeaderParams []ServerParamDef
+	// FormFields are the fields of a form body (formData parameters, or a form or multipart body)
+	FormFields   []ServerParamDef
+	HasBody      bool
+	BodyRequired bool
+	BodyType     string
+	ResponseType string
+	// Codec expressions converting mapped types (Date, bigint) on the wire
+	RequestCodec  string
+	ResponseCodec string
+}
+
+// ServerParamDef is a request parameter; Expr reads and parses it from the RawRequest
+type ServerParamDef struct {
+	Key      string
+	Type     string
+	Required bool
+	Doc      string
+	Expr     string
+}
+
+// Names exported by the runtime and the index that generated interfaces must not take
+var serverReservedNames = []string{
+	"HttpError", "HandlerResponse", "HttpMethod", "RawRequest", "RawResponse", "Route", "ParamKind",
+	"App", "ApiHandlers",
+}
+
+var (
+	routeParamPattern = regexp.MustCompile(`\{([^}]*)\}`)
+	nonWordPattern    = regexp.MustCompile(`\W`)
+)
+
+// Generate the route handlers of -server in server/, typed with the client's types/
+func generateServerHandlers(api *ir.API, config Config) error {
+	serverPath := filepath.Join(config.OutputPath, "server")
+	if err := os.MkdirAll(serverPath, 0755); err != nil {
+		return err
+	}
+
+	resourceTmpl, err := loadTemplate("server/resource.tmpl")
+	if err != nil {
+		return err
+	}
+
+	decls := declsByName(api)
+	codecNeeds := findSchemasNeedingCodecs(api, config)
+	taken := make(map[string]bool)
+	for _, name := range serverReservedNames {
+		taken[name] = true
+	}
+	reserve := func(name string) string {
+		name = uniqueName(name, func(n string) bool { return taken[n] })
+		taken[name] = true
+		return name
+	}
+
+	// An operation listed by several resources (several tags) is served by the first one only, so
+	// that registering every resource does not add its route twice
+	served := make(map[*ir.Operation]bool)
+	var resources []ServerResourceData
+	for _, resource := range api.Resources {
+		data := ServerResourceData{
+			ResourceName:      toTitleCase(resource.Name),
+			ResourceNameLower: strings.ToLower(resource.Name),
+			FileName:          fileSegment(strings.ToLower(resource.Name)),
+		}
+		data.Handlers = reserve(data.ResourceName + "Handlers")
+		data.Routes = reserve(data.ResourceNameLower + "Routes")
+
+		for _, op := range resource.Operations {
+			if served[op] {
+				continue
+			}
+			served[op] = true
+			operation, err := serverOperation(op, config, decls, codecNeeds)
+			if err != nil {
+				return err
+			}
+			operation.RequestName = reserve(toTitleCase(op.ID) + "Request")
+			data.Codecs = data.Codecs || operation.RequestCodec != "" || operation.ResponseCodec != ""
+			data.Forms = data.Forms || len(operation.FormFields) > 0
+			data.Operations = append(data.Operations, operation)
+		}
+		if len(data.Operations) == 0 {
+			continue
+		}
+
+		content, err := executeTemplate(resourceTmpl, data)
+		if err != nil {
+			return err
+		}
+		filePath, err := outputPath(serverPath, data.FileName+".ts")
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		resources = append(resources, data)
+	}
+
+	for name, fileName := range map[string]string{
+		"server/runtime.tmpl":               "runtime.ts",
+		"server/" + config.Server + ".tmpl": config.Server + ".ts",
+	} {
+		tmpl, err := loadTemplate(name)
+		if err != nil {
+			return err
+		}
+		content, err := executeTemplate(tmpl, struct{}{})
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(serverPath, fileName), content); err != nil {
+			return err
+		}
+	}
+
+	indexTmpl, err := loadTemplate("server/index.tmpl")
+	if err != nil {
+		return err
+	}
+	content, err := executeTemplate(indexTmpl, struct {
+		Framework string
+		Resources []ServerResourceData
+	}{config.Server, resources})
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(serverPath, "index.ts"), content)
+}
+
+// Describe an operation for the server templates
+func serverOperation(op *ir.Operation, config Config, decls map[string]*ir.Decl, codecNeeds map[string]bool) (ServerOperationDef, error) {
+	lines := splitDocText(operationDocText(op))
+	if op.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	operation := ServerOperationDef{
+		Name:         op.ID,
+		Doc:          docComment(lines, "  "),
+		Method:       strings.ToLower(op.Method),
+		Status:       op.SuccessStatus(),
+		ResponseType: "void",
+	}
+
+	routePath, routeNames := colonRoutePath(op.Path)
+	operation.RoutePath = jsSingleQuoted(routePath)
+
+	var queryWireNames []string
+	for _, param := range op.ParamsIn("query") {
+		queryWireNames = append(queryWireNames, param.Name)
+	}
+	queryNames := propertyNames(queryWireNames, config)
+
+	for _, param := range op.Params {
+		paramType := tsType(param.Type, config, false)
+		kind, array := serverParamKind(param.Type, config, decls)
+		wireName := jsSingleQuoted(param.Name)
+		def := ServerParamDef{
+			Key:      propertyKey(param.Name),
+			Type:     paramType,
+			Required: param.Required,
+			Doc:      docComment(splitDocText(param.Description), "    "),
+		}
+		if param.Deprecated {
+			def.Doc = deprecatedDoc(param.Description, "    ")
+		}
+
+		switch param.In {
+		case "path":
+			routeName, ok := routeNames[param.Name]
+			if !ok {
+				// Declared but missing from the path template
+				continue
+			}
+			def.Required = true
+			def.Expr = fmt.Sprintf("parseParam(%s, request.param(%s), '%s', true)", wireName, jsSingleQuoted(routeName), kind)
+			operation.PathParams = append(operation.PathParams, def)
+		case "query":
+			def.Key = propertyKey(queryNames[param.Name])
+			if array {
+				def.Expr = fmt.Sprintf("parseArrayParam(%s, request.query(%s), '%s', %t)", wireName, wireName, kind, param.Required)
+			} else {
+				def.Expr = fmt.Sprintf("parseParam(%s, request.query(%s)[0], '%s', %t)", wireName, wireName, kind, param.Required)
+			}
+			operation.QueryParams = append(operation.QueryParams, def)
+		case "header":
+			def.Expr = fmt.Sprintf("parseParam(%s, request.header(%s), '%s', %t)", wireName, wireName, kind, param.Required)
+			operation.HeaderParams = append(operation.HeaderParams, def)
+		}
+	}
+
+	fields, _, isForm, err := formFields(op, decls)
+	if err != nil {
+		return ServerOperationDef{}, err
+	}
+	for _, field := range fields {
+		kind, array := serverParamKind(field.Type, config, decls)
+		wireName := jsSingleQuoted(field.Name)
+		def := ServerParamDef{
+			Key:      propertyKey(field.Name),
+			Type:     tsType(field.Type, config, false),
+			Required: field.Required,
+			Doc:      docComment(splitDocText(field.Type.Description), "    "),
+		}
+		if field.Type.Deprecated {
+			def.Doc = deprecatedDoc(field.Type.Description, "    ")
+		}
+		switch {
+		case isFileType(field.Type) && array:
+			def.Type = "File[]"
+			def.Expr = fmt.Sprintf("parseFiles(%s, form, %t)", wireName, field.Required)
+		case isFileType(field.Type):
+			def.Type = "File"
+			def.Expr = fmt.Sprintf("parseFile(%s, form, %t)", wireName, field.Required)
+		case array:
+			def.Expr = fmt.Sprintf("parseArrayParam(%s, formValues(form, %s), '%s', %t)", wireName, wireName, kind, field.Required)
+		default:
+			def.Expr = fmt.Sprintf("parseParam(%s, formValues(form, %s)[0], '%s', %t)", wireName, wireName, kind, field.Required)
+		}
+		operation.FormFields = append(operation.FormFields, def)
+	}
+
+	// Other bodies are only decoded as JSON
+	if !isForm && op.Body != nil && op.Body.IsJSON() && op.Body.Type != nil {
+		operation.HasBody = true
+		operation.BodyRequired = op.Body.Required
+		operation.BodyType = tsType(op.Body.Type, config, false)
+		operation.RequestCodec = getOperationCodec(op.Body.Type, config, codecNeeds)
+	}
+	if response := op.SuccessResponse(); response != nil {
+		operation.ResponseType = tsType(response.Type, config, false)
+		operation.ResponseCodec = getOperationCodec(response.Type, config, codecNeeds)
+	}
+	return operation, nil
+}
+
+// Convert a path template to the :param syntax of Hono, Express and MSW, with the route name of
+// each path parameter. Route parameters are named with word characters, the only names all of them
+// accept.
+func colonRoutePath(path string) (string, map[string]string) {
+	routeNames := make(map[string]string)
+	routeTaken := make(map[string]bool)
+	routePath := routeParamPattern.ReplaceAllStringFunc(path, func(match string) string {
+		name := match[1 : len(match)-1]
+		routeName := nonWordPattern.ReplaceAllString(toIdentifier(name), "_")
+		if routeName == "" {
+			routeName = "param"
+		}
+		routeName = uniqueName(routeName, func(n string) bool { return routeTaken[n] })
+		routeTaken[routeName] = true
+		routeNames[name] = routeName
+		return ":" + routeName
+	})
+	return routePath, routeNames
+}
+
+// Get the runtime ParamKind a parameter is parsed with, and whether it is a repeated (array)
+// parameter
+func serverParamKind(t *ir.Type, config Config, decls map[string]*ir.Decl) (string, bool) {
+	t = resolveRef(t, decls)
+	if t.Kind == ir.Array {
+		kind, _ := serverParamKind(t.Items, config, decls)
+		return kind, true
+	}
+	switch typeMapping(t.Override, t.Format, config) {
+	case "bigint":
+		return "bigint", false
+	case "Date":
+		return "date", false
+	}
+	switch t.Kind {
+	case ir.Integer:
+		return "integer", false
+	case ir.Number:
+		return "number", false
+	case ir.Boolean:
+		return "boolean", false
+	}
+	return "string", false
+}
+
+// Follow references to the declared type
+func resolveRef(t *ir.Type, decls map[string]*ir.Decl) *ir.Type {
+	for seen := 0; t != nil && t.Kind == ir.Ref && seen < len(decls); seen++ {
+		decl, ok := decls[t.Ref]
+		if !ok {
+			break
+		}
+		t = decl.Type
+	}
+	if t == nil {
+		return &ir.Type{Kind: ir.Any}
+	}
+	return t
+}
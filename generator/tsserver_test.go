@@ -0,0 +1,17 @@
+package generator
+
+import "testing"
+
+func TestServerFormFields(t *testing.T) {
+	config := Config{InputPath: "petstore.json", Language: "typescript", GroupBy: GroupByTag, Server: ServerHono}
+	files := generateFiles(t, config, GenerateTypeScript)
+
+	assertContains(t, files, "server/pet.ts",
+		"  form: {\n    /** Updated name of the pet */\n    name?: string;\n",
+		"    /** file to upload */\n    file?: File;\n",
+		"      form: await parseForm(request, form => ({\n"+
+			"        name: parseParam('name', formValues(form, 'name')[0], 'string', false),\n",
+		"        file: parseFile('file', form, false),\n")
+	assertContains(t, files, "server/hono.ts",
+		"form: () => c.req.formData(),")
+}
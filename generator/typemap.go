@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// TypeScript types that exist at runtime or in lib.d.ts and are used as-is when mapped
+var builtinMappedTypes = map[string]bool{
+	"Date":        true,
+	"Blob":        true,
+	"File":        true,
+	"ArrayBuffer": true,
+	"Uint8Array":  true,
+}
+
+var scalarNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9_]*$`)
+
+// ParseTypeMappings parses a "format=Type,format=Type" list into a format to TypeScript type table
+func ParseTypeMappings(value string) (map[string]string, error) {
+	return parseKeyValueList(value, "type mapping", "format=Type")
+}
+
+// Parse a comma-separated list of key=value pairs
+func parseKeyValueList(value, what, expected string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(entry, "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		if !ok || key == "" || val == "" {
+			return nil, fmt.Errorf("invalid %s %q (expected %s)", what, entry, expected)
+		}
+		pairs[key] = val
+	}
+	return pairs, nil
+}
+
+// Get the TypeScript type configured for a type through x-sveger-type or its format, or ""
+func mappedType(t *ir.Type, config Config) string {
+	if t == nil || t.Kind == ir.Ref {
+		return ""
+	}
+	return typeMapping(t.Override, t.Format, config)
+}
+
+// Get the TypeScript type for an x-sveger-type override or a format mapped with -type-map, or ""
+func typeMapping(override, format string, config Config) string {
+	if override != "" {
+		return override
+	}
+	if format != "" {
+		return config.TypeMappings[format]
+	}
+	return ""
+}
+
+// Report whether a mapped type is a branded scalar declared in types/scalars.types.ts
+func isScalarType(tsType string) bool {
+	return scalarNamePattern.MatchString(tsType) && !builtinMappedTypes[tsType]
+}
+
+// Qualify a mapped type for use outside of the types directory
+func qualifyMappedType(tsType string, isTypesFile bool) string {
+	if isScalarType(tsType) && !isTypesFile {
+		return "Types." + tsType
+	}
+	return tsType
+}
+
+// Get the runtime base of a branded scalar from the JSON type it is mapped from
+func scalarBaseType(t *ir.Type) string {
+	switch t.Kind {
+	case ir.Integer, ir.Number:
+		return "number"
+	case ir.Boolean:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// Get the codec converting between the wire value and a mapped type, or "" when none is needed
+func codecForType(t *ir.Type, config Config) string {
+	switch mappedType(t, config) {
+	case "Date":
+		if t.Format == "date" {
+			return "c.date"
+		}
+		return "c.dateTime"
+	case "bigint":
+		return "c.bigint"
+	case "string":
+		if t.Kind == ir.Integer || t.Kind == ir.Number {
+			return "c.numericString"
+		}
+	}
+	return ""
+}
+
+type ScalarDef struct {
+	Name     string
+	BaseType string
+}
+
+// Collect the branded scalars used by a list of types, sorted by name. A scalar used with several
+// JSON types takes the base type of its first use.
+func collectScalars(types []*ir.Type, config Config) []ScalarDef {
+	found := make(map[string]string)
+	for _, t := range types {
+		ir.Walk(t, func(t *ir.Type) {
+			if tsType := mappedType(t, config); isScalarType(tsType) {
+				if _, ok := found[tsType]; !ok {
+					found[tsType] = scalarBaseType(t)
+				}
+			}
+		})
+	}
+
+	scalars := make([]ScalarDef, 0, len(found))
+	for name, baseType := range found {
+		scalars = append(scalars, ScalarDef{Name: name, BaseType: baseType})
+	}
+	sort.Slice(scalars, func(i, j int) bool {
+		return scalars[i].Name < scalars[j].Name
+	})
+	return scalars
+}
+
+// Collect the branded scalars of a set of declarations
+func collectDeclScalars(decls map[string]*ir.Decl, config Config) []ScalarDef {
+	types := make([]*ir.Type, 0, len(decls))
+	for _, name := range sortedKeys(decls) {
+		types = append(types, decls[name].Type)
+	}
+	return collectScalars(types, config)
+}
+
+// Collect the branded scalars used anywhere in the API: declarations, parameters, bodies and
+// responses
+func collectAPIScalars(api *ir.API, config Config) []ScalarDef {
+	var types []*ir.Type
+	for _, decl := range api.Types {
+		types = append(types, decl.Type)
+	}
+	for _, resource := range api.Resources {
+		for _, op := range resource.Operations {
+			types = append(types, operationTypes(op)...)
+		}
+	}
+	return collectScalars(types, config)
+}
+
+func scalarNames(scalars []ScalarDef) map[string]bool {
+	names := make(map[string]bool, len(scalars))
+	for _, scalar := range scalars {
+		names[scalar.Name] = true
+	}
+	return names
+}
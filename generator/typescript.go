@@ -6,23 +6,59 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
+	"github.com/velogo-dev/sveger/generator/ir"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	InputPath        string
-	OutputPath       string
-	Language         string
-	SplitFiles       bool
-	UseAxios         bool
-	BaseURL          string
-	Timeout          string
-	AuthType         string
-	WithInterceptors bool
+	InputPath         string
+	OutputPath        string
+	Language          string
+	SplitFiles        bool
+	UseAxios          bool
+	BaseURL           string
+	Timeout           string
+	AuthType          string
+	WithInterceptors  bool
+	SvelteKit         bool
+	Zod               bool
+	ZodValidate       bool
+	ValidateResponses bool
+	Constraints       bool
+	// TypeMappings maps schema formats to TypeScript types (e.g. date-time -> Date)
+	TypeMappings      map[string]string
+	ExcludeDeprecated bool
+	// Operation filters; an operation is kept if it matches any include filter and no exclude filter
+	IncludeTags  []string
+	ExcludeTags  []string
+	IncludePaths []string
+	IncludeOps   []string
+	// Resource grouping: strategy (tag, tags, path, extension, flat), path options and renames
+	GroupBy         string
+	PathSegment     int
+	StripPrefix     string
+	ResourceRenames map[string]string
+	// PropertyNaming is "original" (wire names) or "camel" (camelCase with generated mappers)
+	PropertyNaming string
+	// EnumStyle is auto, union, enum, const-enum or const-object
+	EnumStyle string
+	// GoPackage names the package generated with -lang go (default: the output directory name)
+	GoPackage string
+	// Server is the framework (hono, express) route handlers are generated for in server/, or empty
+	Server string
+	// Mocks generates MSW request handlers answering with spec examples in mocks/
+	Mocks bool
+	// Factories generates seeded build<Schema> data builders in factories/
+	Factories bool
+	// DumpIR is a file the intermediate model is written to as JSON, for debugging
+	DumpIR string
 }
 
 type OpenAPISpec struct {
@@ -54,13 +90,42 @@ type PathItem struct {
 	Patch  *Operation `yaml:"patch,omitempty" json:"patch,omitempty"`
 }
 
+// PathOperation is an operation together with its HTTP method
+type PathOperation struct {
+	name string
+	op   *Operation
+}
+
+// Get the operations defined on a path, in a fixed method order
+func (p PathItem) operations() []PathOperation {
+	var ops []PathOperation
+	for _, method := range []PathOperation{
+		{"GET", p.Get},
+		{"POST", p.Post},
+		{"PUT", p.Put},
+		{"DELETE", p.Delete},
+		{"PATCH", p.Patch},
+	} {
+		if method.op != nil {
+			ops = append(ops, method)
+		}
+	}
+	return ops
+}
+
 type Operation struct {
 	OperationID string              `yaml:"operationId" json:"operationId"`
 	Summary     string              `yaml:"summary" json:"summary"`
+	Description string              `yaml:"description" json:"description"`
 	Tags        []string            `yaml:"tags" json:"tags"`
 	Parameters  []Parameter         `yaml:"parameters" json:"parameters"`
 	RequestBody *RequestBody        `yaml:"requestBody" json:"requestBody"`
 	Responses   map[string]Response `yaml:"responses" json:"responses"`
+	Deprecated  bool                `yaml:"deprecated" json:"deprecated"`
+	// XSvegerIgnore leaves the operation out of the generated client
+	XSvegerIgnore bool `yaml:"x-sveger-ignore" json:"x-sveger-ignore"`
+	// XSvegerResource names the resource the operation is grouped under with -group-by extension
+	XSvegerResource string `yaml:"x-sveger-resource" json:"x-sveger-resource"`
 }
 
 type Parameter struct {
@@ -69,9 +134,27 @@ type Parameter struct {
 	Required    bool    `yaml:"required" json:"required"`
 	Schema      *Schema `yaml:"schema" json:"schema"`
 	Description string  `yaml:"description" json:"description"`
+	Deprecated  bool    `yaml:"deprecated" json:"deprecated"`
 	// Swagger 2.0 direct type fields
-	Type   string `yaml:"type" json:"type"`
-	Format string `yaml:"format" json:"format"`
+	Type   string        `yaml:"type" json:"type"`
+	Format string        `yaml:"format" json:"format"`
+	Items  *Schema       `yaml:"items" json:"items"`
+	Enum   []interface{} `yaml:"enum" json:"enum"`
+}
+
+// Get the schema describing a parameter's value, built from the direct type fields for Swagger 2.0
+func (p Parameter) typeSchema() *Schema {
+	if p.Schema != nil {
+		return p.Schema
+	}
+	if p.Type == "" {
+		return nil
+	}
+	if p.Type == "file" {
+		// Swagger 2.0 file uploads are binary strings in OpenAPI 3
+		return &Schema{Type: "string", Format: "binary"}
+	}
+	return &Schema{Type: p.Type, Format: p.Format, Items: p.Items, Enum: p.Enum}
 }
 
 type RequestBody struct {
@@ -80,13 +163,23 @@ type RequestBody struct {
 }
 
 type MediaTypeObject struct {
-	Schema *Schema `yaml:"schema" json:"schema"`
+	Schema   *Schema                  `yaml:"schema" json:"schema"`
+	Example  interface{}              `yaml:"example" json:"example"`
+	Examples map[string]ExampleObject `yaml:"examples" json:"examples"`
+}
+
+// ExampleObject is a named example of a media type
+type ExampleObject struct {
+	Summary string      `yaml:"summary" json:"summary"`
+	Value   interface{} `yaml:"value" json:"value"`
 }
 
 type Response struct {
 	Description string                     `yaml:"description" json:"description"`
 	Content     map[string]MediaTypeObject `yaml:"content" json:"content"` // OpenAPI 3.0
 	Schema      *Schema                    `yaml:"schema" json:"schema"`   // Swagger 2.0
+	// Examples are the Swagger 2.0 examples by media type
+	Examples map[string]interface{} `yaml:"examples" json:"examples"`
 }
 
 type Components struct {
@@ -105,21 +198,100 @@ type Schema struct {
 	AnyOf         []*Schema          `yaml:"anyOf" json:"anyOf"`
 	Enum          []interface{}      `yaml:"enum" json:"enum"`
 	XEnumVarnames []string           `yaml:"x-enum-varnames" json:"x-enum-varnames"`
+	XEnumNames    []string           `yaml:"x-enumNames" json:"x-enumNames"`
+	// XEnumDescriptions documents each enum value, in the order of Enum
+	XEnumDescriptions []string `yaml:"x-enum-descriptions" json:"x-enum-descriptions"`
+	Nullable          bool     `yaml:"nullable" json:"nullable"`
+	XNullable         bool     `yaml:"x-nullable" json:"x-nullable"` // Swagger 2.0
+	// Validation constraints
+	Minimum   *float64 `yaml:"minimum" json:"minimum"`
+	Maximum   *float64 `yaml:"maximum" json:"maximum"`
+	MinLength *int     `yaml:"minLength" json:"minLength"`
+	MaxLength *int     `yaml:"maxLength" json:"maxLength"`
+	Pattern   string   `yaml:"pattern" json:"pattern"`
+	MinItems  *int     `yaml:"minItems" json:"minItems"`
+	MaxItems  *int     `yaml:"maxItems" json:"maxItems"`
+	// Documentation
+	Description string      `yaml:"description" json:"description"`
+	Default     interface{} `yaml:"default" json:"default"`
+	Example     interface{} `yaml:"example" json:"example"`
+	Deprecated  bool        `yaml:"deprecated" json:"deprecated"`
+	// XSvegerType overrides the generated TypeScript type for this schema
+	XSvegerType string `yaml:"x-sveger-type" json:"x-sveger-type"`
+	// XSvegerIgnore leaves the property out of the generated types
+	XSvegerIgnore bool `yaml:"x-sveger-ignore" json:"x-sveger-ignore"`
 }
 
-func GenerateTypeScript(config Config) error {
-	spec, err := loadOpenAPISpec(config.InputPath)
+// IsNullable reports whether the schema allows null (OpenAPI 3.0 nullable or Swagger 2.0 x-nullable)
+func (s *Schema) IsNullable() bool {
+	return s != nil && (s.Nullable || s.XNullable)
+}
+
+// Report summarizes a generation run
+type Report struct {
+	// Deprecated lists every deprecated operation, parameter, schema and property that was emitted
+	Deprecated []string
+	// ExcludedDeprecated counts the deprecated operations left out by -exclude-deprecated
+	ExcludedDeprecated int
+	// FilteredOperations counts the operations left out by filters or x-sveger-ignore
+	FilteredOperations int
+	// PrunedSchemas counts the schemas dropped because no remaining operation reaches them
+	PrunedSchemas int
+	// Renames describes the schemas and operations renamed to valid, unique identifiers
+	Renames []string
+}
+
+func GenerateTypeScript(config Config) (*Report, error) {
+	api, report, err := prepareSpec(config)
 	if err != nil {
-		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
+		return nil, err
 	}
 
 	err = os.MkdirAll(config.OutputPath, 0755)
 	if err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	// Generate new structured API client
-	return generateStructuredApiClient(spec, config)
+	if err := generateStructuredApiClient(api, config); err != nil {
+		return nil, err
+	}
+
+	report.Deprecated = collectDeprecations(api)
+	return report, nil
+}
+
+// Load the spec, apply the filters and name normalization shared by every target language and
+// build the intermediate model (written to -dump-ir when set)
+func prepareSpec(config Config) (*ir.API, *Report, error) {
+	spec, err := loadOpenAPISpec(config.InputPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	if err := checkOutputNames(spec); err != nil {
+		return nil, nil, err
+	}
+
+	report := &Report{}
+	if config.ExcludeDeprecated {
+		report.ExcludedDeprecated = excludeDeprecatedOperations(spec)
+	}
+	report.FilteredOperations = filterOperations(spec, config)
+	removeIgnoredProperties(spec)
+	if report.ExcludedDeprecated > 0 || report.FilteredOperations > 0 {
+		// Only generate the types the remaining operations can reach
+		report.PrunedSchemas = pruneUnreachableSchemas(spec)
+	}
+	report.Renames = normalizeNames(spec)
+
+	api := buildAPI(spec, config)
+	if config.DumpIR != "" {
+		if err := writeIR(api, config.DumpIR); err != nil {
+			return nil, nil, fmt.Errorf("failed to write intermediate model: %w", err)
+		}
+	}
+	return api, report, nil
 }
 
 func loadOpenAPISpec(path string) (*OpenAPISpec, error) {
@@ -143,40 +315,82 @@ func loadOpenAPISpec(path string) (*OpenAPISpec, error) {
 	return &spec, nil
 }
 
-func getTypeFromSchema(schema *Schema, spec *OpenAPISpec) string {
-	return getTypeFromSchemaWithContext(schema, spec, false)
+// Get all named schemas from both OpenAPI 3.0 components and Swagger 2.0 definitions
+func getAllSchemas(spec *OpenAPISpec) map[string]*Schema {
+	allSchemas := make(map[string]*Schema)
+	for name, schema := range spec.Components.Schemas {
+		allSchemas[name] = schema
+	}
+	for name, schema := range spec.Definitions {
+		allSchemas[name] = schema
+	}
+	return allSchemas
 }
 
-func getTypeFromSchemaWithContext(schema *Schema, spec *OpenAPISpec, isTypesFile bool) string {
-	if schema == nil {
+// Render a type as a TypeScript type expression. Outside of the types directory references are
+// qualified with the Types namespace.
+func tsType(t *ir.Type, config Config, isTypesFile bool) string {
+	if t == nil {
 		return "any"
 	}
 
-	if schema.Ref != "" {
-		refName := getRefName(schema.Ref)
+	baseType := tsBaseType(t, config, isTypesFile)
+	if t.Nullable && baseType != "any" {
+		return baseType + " | null"
+	}
+	return baseType
+}
+
+func tsBaseType(t *ir.Type, config Config, isTypesFile bool) string {
+	if t.Kind == ir.Ref {
 		if isTypesFile {
-			return refName
+			return t.Ref
 		}
-		return "Types." + refName
+		return "Types." + t.Ref
+	}
+
+	if mapped := typeMapping(t.Override, t.Format, config); mapped != "" {
+		return qualifyMappedType(mapped, isTypesFile)
+	}
+
+	// Inline enums become literal unions
+	if len(t.Enum) > 0 {
+		return strings.Join(enumLiterals(t.Enum), " | ")
 	}
 
-	switch schema.Type {
-	case "string":
+	switch t.Kind {
+	case ir.Intersection:
+		return composeTypes(t.Variants, " & ", config, isTypesFile)
+	case ir.Union:
+		return composeTypes(t.Variants, " | ", config, isTypesFile)
+	case ir.Object:
+		return inlineObjectType(t, config, isTypesFile)
+	case ir.String:
 		return "string"
-	case "number", "integer":
+	case ir.Number, ir.Integer:
 		return "number"
-	case "boolean":
+	case ir.Boolean:
 		return "boolean"
-	case "array":
-		itemType := getTypeFromSchemaWithContext(schema.Items, spec, isTypesFile)
-		return fmt.Sprintf("%s[]", itemType)
-	case "object":
-		return "any"
+	case ir.Array:
+		return arrayOf(tsType(t.Items, config, isTypesFile))
+	case ir.Map:
+		if t.Values == nil || t.Values.Kind == ir.Any {
+			return "any"
+		}
+		return fmt.Sprintf("Record<string, %s>", tsType(t.Values, config, isTypesFile))
 	default:
 		return "any"
 	}
 }
 
+// Build an array type, parenthesizing union item types
+func arrayOf(itemType string) string {
+	if strings.Contains(itemType, " | ") {
+		return fmt.Sprintf("(%s)[]", itemType)
+	}
+	return itemType + "[]"
+}
+
 func getRefName(ref string) string {
 	parts := strings.Split(ref, "/")
 	name := parts[len(parts)-1]
@@ -185,10 +399,16 @@ func getRefName(ref string) string {
 
 func sanitizeTypeName(name string) string {
 	// Sanitize the name to be a valid TypeScript identifier
-	// Replace dots with underscores and remove other invalid characters
-	name = strings.ReplaceAll(name, ".", "_")
-	name = strings.ReplaceAll(name, "-", "_")
-	name = strings.ReplaceAll(name, " ", "_")
+	// Replace dots, dashes, spaces and other invalid characters with underscores
+	name = strings.Map(func(r rune) rune {
+		if isIdentifierRune(r) {
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "_" + name
+	}
 
 	return name
 }
@@ -236,147 +456,98 @@ func generateOperationID(httpMethod, path string) string {
 	return result.String()
 }
 
-func getOperationDescription(op *Operation) string {
+func getOperationDescription(op *ir.Operation) string {
+	if strings.TrimSpace(op.Description) != "" {
+		return op.Description
+	}
 	if op.Summary != "" {
 		return op.Summary
 	}
 	return "API operation"
 }
 
-func getOperationSummary(op *Operation) string {
+func getOperationSummary(op *ir.Operation) string {
 	if op.Summary != "" {
 		return op.Summary
 	}
 	return "API operation"
 }
 
-func getOperationTags(op *Operation) string {
+func getOperationTags(op *ir.Operation) string {
 	if len(op.Tags) > 0 {
 		return strings.Join(op.Tags, ", ")
 	}
 	return "API"
 }
 
-func getParamTypeString(param Parameter) string {
-	// Check OpenAPI 3.0 style (with Schema)
-	if param.Schema != nil {
-		switch param.Schema.Type {
-		case "string":
-			return "string"
-		case "number", "integer":
-			return "number"
-		case "boolean":
-			return "boolean"
-		case "array":
-			if param.Schema.Items != nil {
-				itemType := getParamTypeFromDirectType(param.Schema.Items.Type)
-				return itemType + "[]"
+// Join the types of allOf (" & ") or oneOf/anyOf (" | ") members. References stay named, so
+// recursive schemas are rendered without expanding them.
+func composeTypes(variants []*ir.Type, separator string, config Config, isTypesFile bool) string {
+	seen := make(map[string]bool)
+	parts := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		part := tsType(variant, config, isTypesFile)
+		if part == "any" {
+			if separator == " | " {
+				return "any"
 			}
-			return "any[]"
-		default:
-			return "any"
+			// any would swallow the intersection
+			continue
+		}
+		if hasTopLevelOperator(part) {
+			part = "(" + part + ")"
+		}
+		if !seen[part] {
+			seen[part] = true
+			parts = append(parts, part)
 		}
 	}
-
-	// Check Swagger 2.0 style (direct type fields)
-	if param.Type != "" {
-		return getParamTypeFromDirectType(param.Type)
-	}
-
-	return "any"
-}
-
-func getParamTypeFromDirectType(paramType string) string {
-	switch paramType {
-	case "string":
-		return "string"
-	case "number", "integer":
-		return "number"
-	case "boolean":
-		return "boolean"
-	case "array":
-		return "any[]"
-	default:
+	if len(parts) == 0 {
 		return "any"
 	}
+	return strings.Join(parts, separator)
 }
 
-func generateReturnType(op *Operation, spec *OpenAPISpec) string {
-	// Check both OpenAPI 3.0 and Swagger 2.0 responses
-	for code, response := range op.Responses {
-		if code == "200" || code == "201" {
-			// OpenAPI 3.0 style (with content)
-			if response.Content != nil {
-				for _, content := range response.Content {
-					if content.Schema != nil {
-						returnType := getTypeFromSchema(content.Schema, spec)
-						if returnType != "any" {
-							return returnType
-						}
-					}
-				}
-			}
-			// Swagger 2.0 style (direct schema)
-			if response.Schema != nil {
-				returnType := getTypeFromSchema(response.Schema, spec)
-				if returnType != "any" {
-					return returnType
-				}
-
-				// Handle allOf schemas more specifically
-				if len(response.Schema.AllOf) > 0 {
-					return generateAllOfReturnType(response.Schema.AllOf, spec)
-				}
+// Report whether a type expression has a union or intersection outside of any brackets
+func hasTopLevelOperator(tsType string) bool {
+	depth := 0
+	for i, r := range tsType {
+		switch r {
+		case '{', '(', '[', '<':
+			depth++
+		case '}', ')', ']', '>':
+			depth--
+		case '|', '&':
+			if depth == 0 && i > 0 && tsType[i-1] == ' ' {
+				return true
 			}
 		}
 	}
-
-	return "any"
+	return false
 }
 
-// Generate return type for allOf schemas
-func generateAllOfReturnType(allOfSchemas []*Schema, spec *OpenAPISpec) string {
-	// Look for data structure in allOf
-	for _, subSchema := range allOfSchemas {
-		if subSchema.Properties != nil {
-			// Check if this has a data property with array of specific types
-			if dataProperty, ok := subSchema.Properties["data"]; ok {
-				if len(dataProperty.AllOf) > 0 {
-					// Look for the actual data array in nested allOf
-					for _, dataSubSchema := range dataProperty.AllOf {
-						if dataSubSchema.Properties != nil {
-							if arrayProperty, ok := dataSubSchema.Properties["data"]; ok {
-								if arrayProperty.Type == "array" && arrayProperty.Items != nil {
-									if arrayProperty.Items.Ref != "" {
-										itemType := getTypeFromSchema(arrayProperty.Items, spec)
-										// Create a response interface with the specific array type
-										return fmt.Sprintf("{ data: { data: %s[]; pagination: any }; error: boolean; message: string }", itemType)
-									}
-								}
-							}
-						}
-					}
-				}
-				// Simple data property
-				if dataProperty.Type == "array" && dataProperty.Items != nil {
-					if dataProperty.Items.Ref != "" {
-						itemType := getTypeFromSchema(dataProperty.Items, spec)
-						return fmt.Sprintf("%s[]", itemType)
-					}
-				}
-			}
-		}
-
-		// Check for direct ref to useful types
-		if subSchema.Ref != "" {
-			refType := getTypeFromSchema(subSchema, spec)
-			if refType != "any" && !strings.Contains(refType, "pkg_") && !strings.Contains(refType, "fiber_") {
-				return refType
-			}
+// Render an inline object as a TypeScript object literal type
+func inlineObjectType(t *ir.Type, config Config, isTypesFile bool) string {
+	names := fieldNames(t.Fields, config)
+	fields := append([]*ir.Field{}, t.Fields...)
+	sort.Slice(fields, func(i, j int) bool { return names[fields[i].Name] < names[fields[j].Name] })
+
+	members := make([]string, 0, len(fields))
+	for _, field := range fields {
+		optional := "?"
+		if field.Required {
+			optional = ""
 		}
+		members = append(members, fmt.Sprintf("%s%s: %s", propertyKey(names[field.Name]), optional, tsType(field.Type, config, isTypesFile)))
 	}
+	return "{ " + strings.Join(members, "; ") + " }"
+}
 
-	// Fallback to any if we can't determine the type
+// Get the type an operation resolves to: its 200/201 response body, or any
+func tsReturnType(op *ir.Operation, config Config) string {
+	if response := op.SuccessResponse(); response != nil {
+		return tsType(response.Type, config, false)
+	}
 	return "any"
 }
 
@@ -392,6 +563,8 @@ func writeFile(path, content string) error {
 
 // Template data structures
 type TypeDef struct {
+	Doc         string
+	Constraints string
 	IsReference bool
 	IsInterface bool
 	IsArray     bool
@@ -402,30 +575,42 @@ type TypeDef struct {
 	Properties  map[string]PropertyDef
 	EnumValues  []string
 	EnumMembers []EnumMember
+	// EnumStyle selects how EnumMembers are declared (enum, const-enum or const-object)
+	EnumStyle string
 }
 
 type EnumMember struct {
+	Doc   string
 	Name  string
 	Value string
 }
 
 type PropertyDef struct {
+	Doc string
+	// Key is the property name as written in the interface (quoted when not an identifier)
+	Key      string
 	Type     string
 	Optional bool
 }
 
 type TypesTemplateData struct {
-	Types map[string]TypeDef
+	Types           map[string]TypeDef
+	WithConstraints bool
+	ScalarImports   []string
+	// Imports lists the types declared in other resources' files
+	Imports []SchemaImport
 }
 
 type MethodDef struct {
-	Name            string
-	Params          string
-	ReturnType      string
-	MethodBody      string
-	Method          string
-	Path            string
-	PathTemplate    string
+	Name         string
+	Params       string
+	ReturnType   string
+	MethodBody   string
+	Method       string
+	Path         string
+	PathTemplate string
+	// PathLiteral is PathTemplate as a quoted JavaScript string
+	PathLiteral     string
 	HasConfig       bool
 	HasQueryParams  bool
 	HasFormData     bool
@@ -441,19 +626,42 @@ type MethodDef struct {
 	Summary         string
 	Tags            string
 	HttpMethod      string
+	// Zod expression for the success response, rendered when ValidateResponse is set
+	ResponseSchema   string
+	ValidateResponse bool
+	// Validator expression for the success response, rendered when UseValidators is set
+	ResponseValidator string
+	UseValidators     bool
+	// Codec expressions converting mapped types (Date, bigint) on the wire
+	RequestCodec  string
+	ResponseCodec string
+	// Deprecation JSDoc: the operation itself and deprecated path parameters
+	Deprecated bool
+	ParamDocs  []string
+	// MapQuery is set when query parameter names differ from their wire names
+	MapQuery bool
 }
 
 type ParamDef struct {
+	// Name is the TypeScript property name, ParamName the wire name; Key and WireKey are their
+	// object key forms
 	Name        string
 	ParamName   string
+	Key         string
+	WireKey     string
 	Required    bool
 	Type        string
 	Description string
+	// Doc is the escaped JSDoc comment rendered above the parameter
+	Doc string
 }
 
 type PathParamDef struct {
+	// Name is the TypeScript identifier, ParamName the name in the path template and Key its
+	// object key form
 	Name        string
 	ParamName   string
+	Key         string
 	IsLast      bool
 	Type        string
 	Description string
@@ -512,99 +720,130 @@ func executeTemplate(tmpl *template.Template, data any) (string, error) {
 	return buf.String(), nil
 }
 
-// Convert schema to TypeDef for template
-func schemaToTypeDef(name string, schema *Schema, spec *OpenAPISpec) TypeDef {
-	typeDef := TypeDef{}
-
-	// Handle references
-	if schema.Ref != "" {
-		typeDef.IsReference = true
-		typeDef.RefName = getRefName(schema.Ref)
-		return typeDef
+// Convert a type declaration to a TypeDef for the types template
+func declTypeDef(decl *ir.Decl, config Config) TypeDef {
+	t := decl.Type
+	typeDef := TypeDef{
+		Doc: docComment(typeDocLines(t), ""),
 	}
 
-	// Handle arrays
-	if schema.Type == "array" && schema.Items != nil {
+	switch {
+	case t.Kind == ir.Ref:
+		typeDef.IsReference = true
+		typeDef.RefName = t.Ref
+	case t.Kind == ir.Array:
 		typeDef.IsArray = true
-		typeDef.ItemType = getTypeFromSchemaWithContext(schema.Items, spec, true)
-		return typeDef
-	}
-
-	// Handle enums
-	if len(schema.Enum) > 0 {
+		typeDef.ItemType = tsType(t.Items, config, true)
+		typeDef.Type = tsType(t, config, true)
+	case len(t.Enum) > 0:
 		typeDef.IsEnum = true
-
-		// Check if we have x-enum-varnames for proper enum member names
-		if len(schema.XEnumVarnames) == len(schema.Enum) {
-			// Generate proper enum members with names
-			for i, val := range schema.Enum {
-				member := EnumMember{
-					Name:  schema.XEnumVarnames[i],
-					Value: fmt.Sprintf(`"%v"`, val),
-				}
-				typeDef.EnumMembers = append(typeDef.EnumMembers, member)
-			}
-		} else {
-			// Fallback to union type for compatibility
-			for _, val := range schema.Enum {
-				typeDef.EnumValues = append(typeDef.EnumValues, fmt.Sprintf(`"%v"`, val))
-			}
+		typeDef.EnumMembers = enumMembers(t, config)
+		typeDef.EnumStyle = config.EnumStyle
+		if typeDef.EnumMembers == nil {
+			typeDef.EnumValues = enumLiterals(t.Enum)
+			typeDef.Doc = docComment(append(typeDocLines(t), enumDescriptionLines(t)...), "")
 		}
-		return typeDef
-	}
-
-	// Handle objects
-	if schema.Type == "object" || len(schema.Properties) > 0 {
+	case t.Kind == ir.Object || t.Kind == ir.Map:
 		typeDef.IsInterface = true
 		typeDef.Properties = make(map[string]PropertyDef)
 
-		for propName, propSchema := range schema.Properties {
-			prop := PropertyDef{
-				Type:     getTypeFromSchemaWithContext(propSchema, spec, true),
-				Optional: !contains(schema.Required, propName),
+		names := fieldNames(t.Fields, config)
+		for _, field := range t.Fields {
+			typeDef.Properties[names[field.Name]] = PropertyDef{
+				Doc:      docComment(typeDocLines(field.Type), "  "),
+				Key:      propertyKey(names[field.Name]),
+				Type:     tsType(field.Type, config, true),
+				Optional: !field.Required,
 			}
-			typeDef.Properties[propName] = prop
 		}
-		return typeDef
+		typeDef.Constraints = constraintsObject(t, config)
+	default:
+		typeDef.Type = tsType(t, config, true)
 	}
-
-	// Handle primitive types
-	typeDef.Type = getTypeFromSchemaWithContext(schema, spec, true)
 	return typeDef
 }
 
 // Generate structured API client with new directory structure
-func generateStructuredApiClient(spec *OpenAPISpec, config Config) error {
+func generateStructuredApiClient(api *ir.API, config Config) error {
 	// Create directory structure
 	if err := createDirectoryStructure(config.OutputPath); err != nil {
 		return fmt.Errorf("failed to create directory structure: %w", err)
 	}
 
 	// Generate config files
-	if err := generateConfigFiles(spec, config); err != nil {
+	if err := generateConfigFiles(api, config); err != nil {
 		return fmt.Errorf("failed to generate config files: %w", err)
 	}
 
 	// Generate types
-	if err := generateStructuredTypes(spec, config); err != nil {
+	if err := generateStructuredTypes(api, config); err != nil {
 		return fmt.Errorf("failed to generate types: %w", err)
 	}
 
+	// Generate zod schemas
+	if config.Zod {
+		if err := generateZodSchemas(api, config); err != nil {
+			return fmt.Errorf("failed to generate zod schemas: %w", err)
+		}
+	}
+
+	// Generate response validators
+	if config.ValidateResponses {
+		if err := generateResponseValidators(api, config); err != nil {
+			return fmt.Errorf("failed to generate response validators: %w", err)
+		}
+	}
+
+	// Generate codecs for mapped types that need (de)serialization
+	if codecNeeds := findSchemasNeedingCodecs(api, config); len(codecNeeds) > 0 || operationsUseCodecs(api, config) {
+		if err := generateCodecs(api, config, codecNeeds); err != nil {
+			return fmt.Errorf("failed to generate codecs: %w", err)
+		}
+	}
+
 	// Generate utils
 	if err := generateUtilsFiles(config); err != nil {
 		return fmt.Errorf("failed to generate utils files: %w", err)
 	}
 
 	// Generate resources
-	if err := generateResourcesStructure(spec, config); err != nil {
+	if err := generateResourcesStructure(api, config); err != nil {
 		return fmt.Errorf("failed to generate resources: %w", err)
 	}
 
 	// Generate main index file
-	if err := generateMainIndex(spec, config); err != nil {
+	if err := generateMainIndex(api, config); err != nil {
 		return fmt.Errorf("failed to generate main index: %w", err)
 	}
 
+	// Generate SvelteKit helpers
+	if config.SvelteKit {
+		if err := generateSvelteKitHelpers(config); err != nil {
+			return fmt.Errorf("failed to generate SvelteKit helpers: %w", err)
+		}
+	}
+
+	// Generate server route handlers
+	if config.Server != "" {
+		if err := generateServerHandlers(api, config); err != nil {
+			return fmt.Errorf("failed to generate server handlers: %w", err)
+		}
+	}
+
+	// Generate MSW handlers
+	if config.Mocks {
+		if err := generateMockHandlers(api, config); err != nil {
+			return fmt.Errorf("failed to generate mock handlers: %w", err)
+		}
+	}
+
+	// Generate data factories
+	if config.Factories {
+		if err := generateFactories(api, config); err != nil {
+			return fmt.Errorf("failed to generate factories: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -627,7 +866,7 @@ func createDirectoryStructure(outputPath string) error {
 }
 
 // Generate config files
-func generateConfigFiles(spec *OpenAPISpec, config Config) error {
+func generateConfigFiles(api *ir.API, config Config) error {
 	configPath := filepath.Join(config.OutputPath, "config")
 
 	// Generate axios.config.ts
@@ -656,19 +895,23 @@ func generateConfigFiles(spec *OpenAPISpec, config Config) error {
 		return err
 	}
 
-	// Generate constants.ts
-	constantsTmpl, err := loadTemplate("config/constants.tmpl")
+	// Generate fetch-adapter.ts
+	fetchAdapterTmpl, err := loadTemplate("config/fetch-adapter.tmpl")
 	if err != nil {
 		return err
 	}
-
-	// Determine base URL
-	baseURL := config.BaseURL
-	if baseURL == "" && len(spec.Servers) > 0 {
-		baseURL = spec.Servers[0].URL
+	fetchAdapterContent, err := executeTemplate(fetchAdapterTmpl, struct{}{})
+	if err != nil {
+		return err
 	}
-	if baseURL == "" {
-		baseURL = "https://api.example.com"
+	if err := writeFile(filepath.Join(configPath, "fetch-adapter.ts"), fetchAdapterContent); err != nil {
+		return err
+	}
+
+	// Generate constants.ts
+	constantsTmpl, err := loadTemplate("config/constants.tmpl")
+	if err != nil {
+		return err
 	}
 
 	// Determine timeout
@@ -678,7 +921,7 @@ func generateConfigFiles(spec *OpenAPISpec, config Config) error {
 	}
 
 	constantsData := ConfigTemplateData{
-		BaseURL: baseURL,
+		BaseURL: jsSingleQuoted(defaultBaseURL(api, config)),
 		Timeout: timeout,
 	}
 
@@ -694,7 +937,7 @@ func generateConfigFiles(spec *OpenAPISpec, config Config) error {
 }
 
 // Generate structured types
-func generateStructuredTypes(spec *OpenAPISpec, config Config) error {
+func generateStructuredTypes(api *ir.API, config Config) error {
 	typesPath := filepath.Join(config.OutputPath, "types")
 
 	// Generate common.types.ts
@@ -702,7 +945,12 @@ func generateStructuredTypes(spec *OpenAPISpec, config Config) error {
 	if err != nil {
 		return err
 	}
-	commonTypesContent, err := executeTemplate(commonTypesTmpl, struct{}{})
+	scalars := collectAPIScalars(api, config)
+	commonTypesContent, err := executeTemplate(commonTypesTmpl, struct {
+		Scalars map[string]bool
+	}{
+		Scalars: scalarNames(scalars),
+	})
 	if err != nil {
 		return err
 	}
@@ -710,14 +958,38 @@ func generateStructuredTypes(spec *OpenAPISpec, config Config) error {
 		return err
 	}
 
+	// Generate scalars.types.ts with branded types from the type mappings
+	if len(scalars) > 0 {
+		scalarsTmpl, err := loadTemplate("types/scalars-types.tmpl")
+		if err != nil {
+			return err
+		}
+		scalarsContent, err := executeTemplate(scalarsTmpl, struct {
+			Scalars []ScalarDef
+		}{
+			Scalars: scalars,
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(typesPath, "scalars.types.ts"), scalarsContent); err != nil {
+			return err
+		}
+	}
+
 	// Generate resource-specific type files based on tags
-	resources := groupOperationsByTag(spec)
+	owners := schemaOwners(api)
 
-	for resourceName := range resources {
-		resourceTypeFile := fmt.Sprintf("%s.types.ts", strings.ToLower(resourceName))
+	for _, resource := range api.Resources {
+		resourceName := resource.Name
+		resourceTypeFile, err := outputPath(typesPath, fileSegment(strings.ToLower(resourceName))+".types.ts")
+		if err != nil {
+			return err
+		}
 
 		// Filter types that belong to this resource
-		resourceTypes := filterTypesForResource(spec, resourceName)
+		decls := ownedResourceDecls(api, owners, resource)
+		resourceTypes := filterTypesForResource(decls, config)
 
 		if len(resourceTypes) > 0 {
 			typesTmpl, err := loadTemplate("types.tmpl")
@@ -726,7 +998,13 @@ func generateStructuredTypes(spec *OpenAPISpec, config Config) error {
 			}
 
 			data := TypesTemplateData{
-				Types: resourceTypes,
+				Types:           resourceTypes,
+				WithConstraints: config.Constraints,
+				Imports:         foreignSchemaImports(decls, owners, resourceName),
+			}
+			resourceScalars := collectDeclScalars(decls, config)
+			for _, scalar := range resourceScalars {
+				data.ScalarImports = append(data.ScalarImports, scalar.Name)
 			}
 
 			typesContent, err := executeTemplate(typesTmpl, data)
@@ -734,14 +1012,14 @@ func generateStructuredTypes(spec *OpenAPISpec, config Config) error {
 				return err
 			}
 
-			if err := writeFile(filepath.Join(typesPath, resourceTypeFile), typesContent); err != nil {
+			if err := writeFile(resourceTypeFile, typesContent); err != nil {
 				return err
 			}
 		}
 	}
 
 	// Generate types index.ts with only files that actually exist
-	if err := generateTypesIndexWithFiles(spec, config); err != nil {
+	if err := generateTypesIndexWithFiles(api, config); err != nil {
 		return err
 	}
 
@@ -778,309 +1056,153 @@ func generateUtilsFiles(config Config) error {
 	return nil
 }
 
-// Group operations by tag/resource
-func groupOperationsByTag(spec *OpenAPISpec) map[string][]MethodDef {
-	resources := make(map[string][]MethodDef)
-
-	for path, pathItem := range spec.Paths {
-		methods := []struct {
-			name string
-			op   *Operation
-		}{
-			{"GET", pathItem.Get},
-			{"POST", pathItem.Post},
-			{"PUT", pathItem.Put},
-			{"DELETE", pathItem.Delete},
-			{"PATCH", pathItem.Patch},
-		}
-
-		for _, method := range methods {
-			if method.op != nil {
-				// Generate operationId if missing
-				if method.op.OperationID == "" {
-					method.op.OperationID = generateOperationID(method.name, path)
-				}
-
-				// Determine resource name from tags or path
-				resourceName := "default"
-				if len(method.op.Tags) > 0 {
-					resourceName = method.op.Tags[0]
-				} else {
-					// Extract from path
-					pathParts := strings.Split(strings.Trim(path, "/"), "/")
-					if len(pathParts) > 0 {
-						resourceName = pathParts[0]
-					}
-				}
-
-				methodDef := MethodDef{
-					Name:            method.op.OperationID,
-					HttpMethod:      method.name,
-					Path:            path,
-					PathTemplate:    path,
-					Method:          strings.ToLower(method.name),
-					ReturnType:      generateReturnType(method.op, spec),
-					Description:     getOperationDescription(method.op),
-					Summary:         getOperationSummary(method.op),
-					Tags:            getOperationTags(method.op),
-					HasQueryParams:  false,
-					HasPathParams:   false,
-					HasRequestBody:  false,
-					QueryParams:     []ParamDef{},
-					PathParams:      []PathParamDef{},
-					RequestBodyType: "any",
-				}
-
-				// Process parameters
-				for _, param := range method.op.Parameters {
-					switch param.In {
-					case "query":
-						methodDef.HasQueryParams = true
-						queryParam := ParamDef{
-							Name:        param.Name,
-							Type:        getParamTypeString(param),
-							Required:    param.Required,
-							Description: param.Description,
-						}
-						methodDef.QueryParams = append(methodDef.QueryParams, queryParam)
-					case "path":
-						methodDef.HasPathParams = true
-						pathParam := PathParamDef{
-							Name:        param.Name,
-							Type:        getParamTypeString(param),
-							Description: param.Description,
-						}
-						methodDef.PathParams = append(methodDef.PathParams, pathParam)
-					case "body":
-						methodDef.HasRequestBody = true
-						if param.Schema != nil {
-							methodDef.RequestBodyType = getTypeFromSchema(param.Schema, spec)
-						} else {
-							methodDef.RequestBodyType = "any"
-						}
-					}
-				}
-
-				// Handle OpenAPI 3.0 request body
-				if method.op.RequestBody != nil && !methodDef.HasRequestBody {
-					methodDef.HasRequestBody = true
-					if content, ok := method.op.RequestBody.Content["application/json"]; ok && content.Schema != nil {
-						methodDef.RequestBodyType = getTypeFromSchema(content.Schema, spec)
-					} else {
-						methodDef.RequestBodyType = "any"
-					}
-				}
-
-				resources[resourceName] = append(resources[resourceName], methodDef)
-			}
-		}
+// Build the method definitions of a resource's operations
+func resourceMethods(resource *ir.Resource, config Config, codecNeeds map[string]bool) []MethodDef {
+	methods := make([]MethodDef, 0, len(resource.Operations))
+	for _, op := range resource.Operations {
+		methods = append(methods, operationMethod(op, config, codecNeeds))
 	}
-
-	return resources
+	return methods
 }
 
-// Filter types for a specific resource
-func filterTypesForResource(spec *OpenAPISpec, resourceName string) map[string]TypeDef {
-	types := make(map[string]TypeDef)
-	relatedTypes := make(map[string]bool)
-
-	// Get all schemas from both OpenAPI 3.0 and Swagger 2.0
-	allSchemas := make(map[string]*Schema)
-	if spec.Components.Schemas != nil {
-		for name, schema := range spec.Components.Schemas {
-			allSchemas[name] = schema
-		}
-	}
-	if spec.Definitions != nil {
-		for name, schema := range spec.Definitions {
-			allSchemas[name] = schema
-		}
-	}
-
-	// Find types used in operations tagged with this resource
-	for path, pathItem := range spec.Paths {
-		methods := []struct {
-			name string
-			op   *Operation
-		}{
-			{"GET", pathItem.Get},
-			{"POST", pathItem.Post},
-			{"PUT", pathItem.Put},
-			{"DELETE", pathItem.Delete},
-			{"PATCH", pathItem.Patch},
-		}
-
-		for _, method := range methods {
-			if method.op != nil {
-				// Check if this operation belongs to the current resource
-				operationResourceName := "default"
-				if len(method.op.Tags) > 0 {
-					operationResourceName = method.op.Tags[0]
-				} else {
-					// Extract from path
-					pathParts := strings.Split(strings.Trim(path, "/"), "/")
-					if len(pathParts) > 0 {
-						operationResourceName = pathParts[0]
-					}
-				}
-
-				// If this operation belongs to our resource, find all types it uses
-				if strings.EqualFold(operationResourceName, resourceName) {
-					// Find types in parameters
-					for _, param := range method.op.Parameters {
-						if param.Schema != nil {
-							findTypesInSchema(param.Schema, allSchemas, relatedTypes)
-						}
-					}
-
-					// Find types in request body
-					if method.op.RequestBody != nil {
-						for _, content := range method.op.RequestBody.Content {
-							if content.Schema != nil {
-								findTypesInSchema(content.Schema, allSchemas, relatedTypes)
-							}
-						}
-					}
-
-					// Find types in responses
-					for _, response := range method.op.Responses {
-						// OpenAPI 3.0 style
-						if response.Content != nil {
-							for _, content := range response.Content {
-								if content.Schema != nil {
-									findTypesInSchema(content.Schema, allSchemas, relatedTypes)
-								}
-							}
-						}
-						// Swagger 2.0 style
-						if response.Schema != nil {
-							findTypesInSchema(response.Schema, allSchemas, relatedTypes)
-						}
-					}
-				}
+// Build the method definition of an operation
+func operationMethod(op *ir.Operation, config Config, codecNeeds map[string]bool) MethodDef {
+	var responseType *ir.Type
+	if response := op.SuccessResponse(); response != nil {
+		responseType = response.Type
+	}
+	methodDef := MethodDef{
+		Name:              op.ID,
+		HttpMethod:        op.Method,
+		Path:              docInline(op.Path),
+		PathTemplate:      op.Path,
+		PathLiteral:       jsSingleQuoted(op.Path),
+		Method:            strings.ToLower(op.Method),
+		ReturnType:        tsReturnType(op, config),
+		ResponseSchema:    getResponseZodSchema(responseType, config),
+		ResponseValidator: getResponseValidator(responseType),
+		ResponseCodec:     getOperationCodec(responseType, config, codecNeeds),
+		Description:       docMultiline(getOperationDescription(op)),
+		Summary:           docInline(getOperationSummary(op)),
+		Tags:              docInline(getOperationTags(op)),
+		Deprecated:        op.Deprecated,
+		QueryParams:       []ParamDef{},
+		PathParams:        []PathParamDef{},
+		RequestBodyType:   "any",
+	}
+
+	var queryWireNames []string
+	for _, param := range op.ParamsIn("query") {
+		queryWireNames = append(queryWireNames, param.Name)
+	}
+	queryNames := propertyNames(queryWireNames, config)
+
+	for _, param := range op.Params {
+		switch param.In {
+		case "query":
+			methodDef.HasQueryParams = true
+			queryParam := ParamDef{
+				Name:        queryNames[param.Name],
+				ParamName:   param.Name,
+				Key:         propertyKey(queryNames[param.Name]),
+				WireKey:     propertyKey(param.Name),
+				Type:        tsType(param.Type, config, false),
+				Required:    param.Required,
+				Description: param.Description,
+				Doc:         docComment(splitDocText(param.Description), "  "),
 			}
-		}
-	}
-
-	// Also find types that directly match the resource name for completeness
-	for name, schema := range allSchemas {
-		if strings.Contains(strings.ToLower(name), strings.ToLower(resourceName)) ||
-			strings.EqualFold(name, resourceName) {
-			relatedTypes[name] = true
-			findReferencedTypes(schema, allSchemas, relatedTypes)
-		}
-	}
-
-	// Convert all related types to TypeDef
-	for name := range relatedTypes {
-		if schema, exists := allSchemas[name]; exists {
-			sanitizedName := sanitizeTypeName(name)
-			types[sanitizedName] = schemaToTypeDef(name, schema, spec)
-		}
-	}
-
-	return types
-}
-
-// Find types referenced in a schema for type collection
-func findTypesInSchema(schema *Schema, allSchemas map[string]*Schema, relatedTypes map[string]bool) {
-	if schema == nil {
-		return
-	}
-
-	// Check direct reference
-	if schema.Ref != "" {
-		refName := getRefName(schema.Ref)
-		if _, exists := allSchemas[refName]; exists {
-			relatedTypes[refName] = true
-			// Recursively find references in the referenced type
-			if refSchema, ok := allSchemas[refName]; ok {
-				findReferencedTypes(refSchema, allSchemas, relatedTypes)
+			if param.Deprecated {
+				queryParam.Doc = deprecatedDoc(param.Description, "  ")
+			}
+			methodDef.MapQuery = methodDef.MapQuery || queryParam.Name != param.Name
+			methodDef.QueryParams = append(methodDef.QueryParams, queryParam)
+		case "path":
+			methodDef.HasPathParams = true
+			methodDef.PathParams = append(methodDef.PathParams, PathParamDef{
+				Name:        pathParamIdentifier(param.Name),
+				ParamName:   param.Name,
+				Key:         propertyKey(param.Name),
+				Type:        tsType(param.Type, config, false),
+				Description: param.Description,
+			})
+			if param.Deprecated {
+				methodDef.ParamDocs = append(methodDef.ParamDocs, deprecatedParamLine(param.Name, param.Description))
 			}
 		}
 	}
 
-	// Check properties
-	if schema.Properties != nil {
-		for _, propSchema := range schema.Properties {
-			findTypesInSchema(propSchema, allSchemas, relatedTypes)
+	if op.Body != nil {
+		methodDef.HasRequestBody = true
+		if op.Body.IsJSON() && op.Body.Type != nil {
+			methodDef.RequestBodyType = tsType(op.Body.Type, config, false)
+			methodDef.RequestCodec = getOperationCodec(op.Body.Type, config, codecNeeds)
 		}
 	}
-
-	// Check array items
-	if schema.Items != nil {
-		findTypesInSchema(schema.Items, allSchemas, relatedTypes)
-	}
-
-	// Check allOf, oneOf, anyOf
-	for _, subSchema := range schema.AllOf {
-		findTypesInSchema(subSchema, allSchemas, relatedTypes)
-	}
-	for _, subSchema := range schema.OneOf {
-		findTypesInSchema(subSchema, allSchemas, relatedTypes)
-	}
-	for _, subSchema := range schema.AnyOf {
-		findTypesInSchema(subSchema, allSchemas, relatedTypes)
+	if config.Zod && config.ZodValidate && methodDef.ResponseSchema != "" {
+		// The zod schema converts the response itself (renamed properties, dates, bigints)
+		methodDef.ResponseCodec = ""
 	}
+	return methodDef
 }
 
-// Find types referenced by a schema
-func findReferencedTypes(schema *Schema, allSchemas map[string]*Schema, relatedTypes map[string]bool) {
-	if schema == nil {
-		return
+// Convert the declarations made by a resource to type definitions
+func filterTypesForResource(decls map[string]*ir.Decl, config Config) map[string]TypeDef {
+	types := make(map[string]TypeDef)
+	for name, decl := range decls {
+		types[name] = declTypeDef(decl, config)
 	}
+	return types
+}
 
-	// Check direct reference
-	if schema.Ref != "" {
-		refName := getRefName(schema.Ref)
-		if _, exists := allSchemas[refName]; exists {
-			relatedTypes[refName] = true
-			// Recursively find references in the referenced type
-			if refSchema, ok := allSchemas[refName]; ok {
-				findReferencedTypes(refSchema, allSchemas, relatedTypes)
+// Collect the declarations used by a resource's operations and those named after the resource,
+// with every declaration they reference, keyed by name
+func collectResourceDecls(api *ir.API, resource *ir.Resource) map[string]*ir.Decl {
+	decls := declsByName(api)
+	related := make(map[string]*ir.Decl)
+	var visit func(t *ir.Type)
+	visit = func(t *ir.Type) {
+		for _, ref := range typeRefs(t) {
+			if decl, ok := decls[ref]; ok && related[ref] == nil {
+				related[ref] = decl
+				visit(decl.Type)
 			}
 		}
 	}
 
-	// Check properties
-	if schema.Properties != nil {
-		for _, propSchema := range schema.Properties {
-			findReferencedTypes(propSchema, allSchemas, relatedTypes)
+	for _, op := range resource.Operations {
+		for _, t := range operationTypes(op) {
+			visit(t)
 		}
 	}
 
-	// Check array items
-	if schema.Items != nil {
-		findReferencedTypes(schema.Items, allSchemas, relatedTypes)
-	}
-
-	// Check allOf, oneOf, anyOf
-	for _, subSchema := range schema.AllOf {
-		findReferencedTypes(subSchema, allSchemas, relatedTypes)
-	}
-	for _, subSchema := range schema.OneOf {
-		findReferencedTypes(subSchema, allSchemas, relatedTypes)
-	}
-	for _, subSchema := range schema.AnyOf {
-		findReferencedTypes(subSchema, allSchemas, relatedTypes)
+	// Also find types that directly match the resource name for completeness
+	for _, decl := range api.Types {
+		if strings.Contains(strings.ToLower(decl.Name), strings.ToLower(resource.Name)) && related[decl.Name] == nil {
+			related[decl.Name] = decl
+			visit(decl.Type)
+		}
 	}
+	return related
 }
 
 // Generate types index file with actual generated files
-func generateTypesIndexWithFiles(spec *OpenAPISpec, config Config) error {
+func generateTypesIndexWithFiles(api *ir.API, config Config) error {
 	typesPath := filepath.Join(config.OutputPath, "types")
-	resources := groupOperationsByTag(spec)
 
 	var content strings.Builder
 	content.WriteString("// Auto-generated types index\n\n")
-	content.WriteString("export * from './common.types';\n")
+	scalars := collectAPIScalars(api, config)
+	content.WriteString(commonTypesExports(api, scalarNames(scalars)))
+	if len(scalars) > 0 {
+		content.WriteString("export * from './scalars.types';\n")
+	}
 
 	// Only add exports for type files that actually have content
+	owners := schemaOwners(api)
 	resourceNames := make([]string, 0)
-	for resourceName := range resources {
-		resourceTypes := filterTypesForResource(spec, resourceName)
-		if len(resourceTypes) > 0 {
-			resourceNames = append(resourceNames, strings.ToLower(resourceName))
+	for _, resource := range api.Resources {
+		if len(ownedResourceDecls(api, owners, resource)) > 0 {
+			resourceNames = append(resourceNames, strings.ToLower(resource.Name))
 		}
 	}
 
@@ -1093,13 +1215,17 @@ func generateTypesIndexWithFiles(spec *OpenAPISpec, config Config) error {
 }
 
 // Generate resources structure
-func generateResourcesStructure(spec *OpenAPISpec, config Config) error {
+func generateResourcesStructure(api *ir.API, config Config) error {
 	resourcesPath := filepath.Join(config.OutputPath, "resources")
-	resources := groupOperationsByTag(spec)
+	codecNeeds := findSchemasNeedingCodecs(api, config)
 
-	for resourceName, operations := range resources {
-		resourceLower := strings.ToLower(resourceName)
-		resourcePath := filepath.Join(resourcesPath, resourceLower)
+	for _, resource := range api.Resources {
+		resourceName := resource.Name
+		operations := resourceMethods(resource, config, codecNeeds)
+		resourcePath, err := outputPath(resourcesPath, fileSegment(strings.ToLower(resourceName)))
+		if err != nil {
+			return err
+		}
 		operationsPath := filepath.Join(resourcePath, "operations")
 
 		// Create resource directory and operations subdirectory
@@ -1109,6 +1235,8 @@ func generateResourcesStructure(spec *OpenAPISpec, config Config) error {
 
 		// Generate individual operation files
 		for _, operation := range operations {
+			operation.ValidateResponse = config.Zod && config.ZodValidate && operation.ResponseSchema != ""
+			operation.UseValidators = config.ValidateResponses && operation.ResponseValidator != ""
 			if err := generateResourceOperation(operation, operationsPath); err != nil {
 				return fmt.Errorf("failed to generate operation %s: %w", operation.Name, err)
 			}
@@ -1144,8 +1272,11 @@ func generateResourceOperation(operation MethodDef, operationsPath string) error
 		return err
 	}
 
-	fileName := fmt.Sprintf("%s.ts", toKebabCase(operation.Name))
-	return writeFile(filepath.Join(operationsPath, fileName), content)
+	filePath, err := outputPath(operationsPath, fileSegment(toKebabCase(operation.Name))+".ts")
+	if err != nil {
+		return err
+	}
+	return writeFile(filePath, content)
 }
 
 // Generate resource API client
@@ -1157,17 +1288,20 @@ func generateResourceApiClient(resourceName string, operations []MethodDef, reso
 
 	// Prepare operation data with file names
 	operationData := make([]struct {
-		Name     string
-		FileName string
+		Name       string
+		FileName   string
+		Deprecated bool
 	}, len(operations))
 
 	for i, op := range operations {
 		operationData[i] = struct {
-			Name     string
-			FileName string
+			Name       string
+			FileName   string
+			Deprecated bool
 		}{
-			Name:     op.Name,
-			FileName: toKebabCase(op.Name),
+			Name:       op.Name,
+			FileName:   toKebabCase(op.Name),
+			Deprecated: op.Deprecated,
 		}
 	}
 
@@ -1175,8 +1309,9 @@ func generateResourceApiClient(resourceName string, operations []MethodDef, reso
 		ResourceName      string
 		ResourceNameLower string
 		Operations        []struct {
-			Name     string
-			FileName string
+			Name       string
+			FileName   string
+			Deprecated bool
 		}
 	}{
 		ResourceName:      toTitleCase(resourceName),
@@ -1189,8 +1324,11 @@ func generateResourceApiClient(resourceName string, operations []MethodDef, reso
 		return err
 	}
 
-	fileName := fmt.Sprintf("%s-api.client.ts", strings.ToLower(resourceName))
-	return writeFile(filepath.Join(resourcePath, fileName), content)
+	filePath, err := outputPath(resourcePath, fileSegment(strings.ToLower(resourceName))+"-api.client.ts")
+	if err != nil {
+		return err
+	}
+	return writeFile(filePath, content)
 }
 
 // Generate resource index
@@ -1215,25 +1353,24 @@ func generateResourceIndex(resourceName string, operations []MethodDef, resource
 }
 
 // Generate main index
-func generateMainIndex(spec *OpenAPISpec, config Config) error {
+func generateMainIndex(api *ir.API, config Config) error {
 	tmpl, err := loadTemplate("main-index.tmpl")
 	if err != nil {
 		return err
 	}
 
-	resources := groupOperationsByTag(spec)
 	resourceData := make([]struct {
 		ResourceName      string
 		ResourceNameLower string
-	}, 0, len(resources))
+	}, 0, len(api.Resources))
 
-	for resourceName := range resources {
+	for _, resource := range api.Resources {
 		resourceData = append(resourceData, struct {
 			ResourceName      string
 			ResourceNameLower string
 		}{
-			ResourceName:      toTitleCase(resourceName),
-			ResourceNameLower: strings.ToLower(resourceName),
+			ResourceName:      toTitleCase(resource.Name),
+			ResourceNameLower: strings.ToLower(resource.Name),
 		})
 	}
 
@@ -1243,12 +1380,16 @@ func generateMainIndex(spec *OpenAPISpec, config Config) error {
 	})
 
 	data := struct {
-		Resources []struct {
+		Zod               bool
+		ValidateResponses bool
+		Resources         []struct {
 			ResourceName      string
 			ResourceNameLower string
 		}
 	}{
-		Resources: resourceData,
+		Zod:               config.Zod,
+		ValidateResponses: config.ValidateResponses,
+		Resources:         resourceData,
 	}
 
 	content, err := executeTemplate(tmpl, data)
@@ -1259,6 +1400,21 @@ func generateMainIndex(spec *OpenAPISpec, config Config) error {
 	return writeFile(filepath.Join(config.OutputPath, "index.ts"), content)
 }
 
+// Generate SvelteKit load/server helpers
+func generateSvelteKitHelpers(config Config) error {
+	tmpl, err := loadTemplate("sveltekit.tmpl")
+	if err != nil {
+		return err
+	}
+
+	content, err := executeTemplate(tmpl, struct{}{})
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(config.OutputPath, "sveltekit.ts"), content)
+}
+
 // Helper functions
 
 // Convert string to kebab-case
@@ -1273,6 +1429,56 @@ func toKebabCase(s string) string {
 	return strings.ToLower(result.String())
 }
 
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Render an object property key, quoting it when it is not a valid identifier
+func propertyKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return jsString(name)
+}
+
+// Render a string as a double-quoted JavaScript string literal
+func jsString(s string) string {
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return `""`
+	}
+	return string(encoded)
+}
+
+// Render a scalar spec value (enum value, default, example) as a JavaScript literal
+func jsLiteral(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case string:
+		return jsString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return jsString(fmt.Sprint(v))
+		}
+		return string(encoded)
+	}
+}
+
+// Render enum values as TypeScript literal types; numbers and booleans stay unquoted
+func enumLiterals(values []interface{}) []string {
+	literals := make([]string, 0, len(values))
+	for _, val := range values {
+		literals = append(literals, jsLiteral(val))
+	}
+	return literals
+}
+
 // Convert string to TitleCase
 func toTitleCase(s string) string {
 	if len(s) == 0 {
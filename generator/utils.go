@@ -0,0 +1,13 @@
+package generator
+
+import "sort"
+
+// Get the keys of a map in sorted order, for output that does not depend on map iteration
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,602 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of a Diagnostic. Errors make a spec invalid; warnings point at parts of it the
+// generators ignore or type as any.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found in a spec by ValidateSpec. Line and Column are 1-based, and 0
+// when unknown; Pointer is a JSON pointer to the offending value.
+type Diagnostic struct {
+	Severity string
+	File     string
+	Line     int
+	Column   int
+	Pointer  string
+	Message  string
+}
+
+// String formats a diagnostic as file:line:column: severity: message (pointer)
+func (d Diagnostic) String() string {
+	location := d.File
+	if d.Line > 0 {
+		location += fmt.Sprintf(":%d", d.Line)
+	}
+	if d.Column > 0 {
+		location += fmt.Sprintf(":%d", d.Column)
+	}
+	text := fmt.Sprintf("%s: %s: %s", location, d.Severity, d.Message)
+	if d.Pointer != "" {
+		text += " (" + d.Pointer + ")"
+	}
+	return text
+}
+
+// HasErrors reports whether any of the diagnostics is an error
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	// References the generators resolve: named schemas of OpenAPI 3 and Swagger 2
+	schemaRefPattern = regexp.MustCompile(`^#/(components/schemas|definitions)/[^/]+$`)
+)
+
+// Methods of a path item that the generators do not emit operations for
+var unsupportedMethods = []string{"head", "options", "trace"}
+
+// Schema keywords the generators ignore
+var unsupportedSchemaKeywords = []string{"not", "patternProperties", "const", "if", "then", "else", "prefixItems"}
+
+type specValidator struct {
+	file        string
+	root        *yaml.Node
+	diagnostics []Diagnostic
+}
+
+// ValidateSpec checks a spec for problems that make the generated code wrong or incomplete:
+// syntax and type errors, unresolved references, duplicate operationIds, path parameters that do
+// not match the path template, operations without responses and constructs the generators do
+// not support. The error is only set when the file cannot be read.
+func ValidateSpec(path string) ([]Diagnostic, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	v := &specValidator{file: path}
+
+	isJSON := strings.HasSuffix(strings.ToLower(path), ".json")
+	var value any
+	if isJSON {
+		// JSON syntax errors are located more precisely by encoding/json
+		var syntaxErr *json.SyntaxError
+		if err := json.Unmarshal(data, &value); errors.As(err, &syntaxErr) {
+			line, column := offsetPosition(data, syntaxErr.Offset)
+			v.diagnostics = append(v.diagnostics, Diagnostic{SeverityError, path, line, column, "", syntaxErr.Error()})
+			return v.diagnostics, nil
+		}
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err == nil && len(document.Content) > 0 {
+		v.root = document.Content[0]
+	} else if isJSON {
+		// Valid JSON that yaml.v3 does not read, such as a \/ escape, is checked without line and
+		// column numbers
+		v.root = new(yaml.Node)
+		if err := v.root.Encode(value); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		v.addParseError(err)
+		return v.diagnostics, nil
+	}
+	if v.root == nil || v.root.Kind != yaml.MappingNode {
+		v.diagnostics = append(v.diagnostics, Diagnostic{SeverityError, path, 1, 1, "", "the spec is not an object"})
+		return v.diagnostics, nil
+	}
+
+	// Values of the wrong type (a list for a string, ...) fail loading the spec, which reads JSON
+	// specs with encoding/json
+	var spec OpenAPISpec
+	if isJSON {
+		if err := json.Unmarshal(data, &spec); err != nil {
+			v.addJSONTypeError(data, err)
+		}
+	} else if err := v.root.Decode(&spec); err != nil {
+		v.addParseError(err)
+	}
+
+	if mappingValue(v.root, "openapi") == nil && mappingValue(v.root, "swagger") == nil {
+		v.add(SeverityError, v.root, nil, "the spec has neither an openapi nor a swagger version")
+	}
+	v.refs(v.root, nil)
+	v.paths()
+	v.componentSchemas()
+
+	sort.SliceStable(v.diagnostics, func(i, j int) bool {
+		a, b := v.diagnostics[i], v.diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return v.diagnostics, nil
+}
+
+// Add a diagnostic about a node at the location given by tokens
+func (v *specValidator) add(severity string, node *yaml.Node, tokens []string, format string, args ...any) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Severity: severity,
+		File:     v.file,
+		Line:     node.Line,
+		Column:   node.Column,
+		Pointer:  specPointer(tokens...),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Add the errors of yaml.Unmarshal or Decode, which report a line but no column
+func (v *specValidator) addParseError(err error) {
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+	for _, message := range messages {
+		d := Diagnostic{Severity: SeverityError, File: v.file, Message: message}
+		if match := yamlLinePattern.FindStringSubmatch(message); match != nil {
+			d.Line, _ = strconv.Atoi(match[1])
+			d.Message = match[2]
+			if node, tokens := v.locate(v.root, nil, d.Line); node != nil {
+				d.Column = node.Column
+				d.Pointer = specPointer(tokens...)
+			}
+		}
+		v.diagnostics = append(v.diagnostics, d)
+	}
+}
+
+// Add an error of encoding/json decoding the spec, located by its offset
+func (v *specValidator) addJSONTypeError(data []byte, err error) {
+	d := Diagnostic{Severity: SeverityError, File: v.file, Message: err.Error()}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		d.Message = fmt.Sprintf("cannot unmarshal %s into %s", typeErr.Value, typeErr.Type)
+		if v.root.Line > 0 {
+			d.Line, _ = offsetPosition(data, typeErr.Offset)
+			if node, tokens := v.locate(v.root, nil, d.Line); node != nil {
+				d.Column = node.Column
+				d.Pointer = specPointer(tokens...)
+			}
+		}
+	}
+	v.diagnostics = append(v.diagnostics, d)
+}
+
+// Find the first value on a line, with its location, for errors that only give the line. A block
+// collection is not taken for the line of its first key: only values on the line of their own key
+// (or sequence item) are.
+func (v *specValidator) locate(node *yaml.Node, tokens []string, line int) (*yaml.Node, []string) {
+	if node == nil {
+		return nil, nil
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			path := appendToken(tokens, node.Content[i].Value)
+			if value := node.Content[i+1]; value.Line == line && node.Content[i].Line == line {
+				return value, path
+			}
+			if found, path := v.locate(node.Content[i+1], path, line); found != nil {
+				return found, path
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			path := appendToken(tokens, strconv.Itoa(i))
+			if item.Line == line {
+				return item, path
+			}
+			if found, path := v.locate(item, path, line); found != nil {
+				return found, path
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Check every reference: external ones are not followed and local ones must resolve. Example
+// data and extensions are skipped, since their "$ref" keys are not references.
+func (v *specValidator) refs(node *yaml.Node, tokens []string) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i].Value, node.Content[i+1]
+			path := appendToken(tokens, key)
+			switch {
+			case key == "$ref" && value.Kind == yaml.ScalarNode:
+				ref := value.Value
+				if !strings.HasPrefix(ref, "#") {
+					v.add(SeverityError, value, path, "external reference %q is not supported", ref)
+				} else if v.resolve(ref) == nil {
+					v.add(SeverityError, value, path, "unresolved reference %q", ref)
+				}
+			case key == "example" || key == "examples" || key == "default" || key == "enum" || strings.HasPrefix(key, "x-"):
+			default:
+				v.refs(value, path)
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			v.refs(item, appendToken(tokens, strconv.Itoa(i)))
+		}
+	}
+}
+
+// Find the node a local reference points to, or nil
+func (v *specValidator) resolve(ref string) *yaml.Node {
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer == "" {
+		return v.root
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil
+	}
+	node := v.root
+	unescaper := strings.NewReplacer("~1", "/", "~0", "~")
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = unescaper.Replace(token)
+		switch node.Kind {
+		case yaml.MappingNode:
+			node = mappingValue(node, token)
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[index]
+		default:
+			return nil
+		}
+		if node == nil {
+			return nil
+		}
+		node = dealias(node)
+	}
+	return node
+}
+
+// Check the operations of every path
+func (v *specValidator) paths() {
+	paths := mappingValue(v.root, "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode || len(paths.Content) == 0 {
+		v.add(SeverityWarning, v.root, nil, "the spec has no paths")
+		return
+	}
+
+	operationIDs := make(map[string]string)
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, item := paths.Content[i].Value, dealias(paths.Content[i+1])
+		tokens := []string{"paths", path}
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		if ref := mappingValue(item, "$ref"); ref != nil {
+			v.add(SeverityWarning, ref, appendToken(tokens, "$ref"), "path item references are not supported; the path is skipped")
+		}
+
+		// Parameters shared by the operations of the path count as declared, but are reported
+		// since the generators only read operation parameters
+		shared := v.pathParams(item, tokens, true)
+		for _, method := range unsupportedMethods {
+			if op := mappingValue(item, method); op != nil {
+				v.add(SeverityWarning, op, appendToken(tokens, method), "%s operations are not supported and are skipped", strings.ToUpper(method))
+			}
+		}
+
+		for _, method := range []string{"get", "post", "put", "delete", "patch"} {
+			op := mappingValue(item, method)
+			if op == nil || dealias(op).Kind != yaml.MappingNode {
+				continue
+			}
+			op = dealias(op)
+			opTokens := appendToken(tokens, method)
+
+			if id := mappingValue(op, "operationId"); id != nil && id.Value != "" {
+				idTokens := appendToken(opTokens, "operationId")
+				if first, ok := operationIDs[id.Value]; ok {
+					v.add(SeverityError, id, idTokens, "duplicate operationId %q, first used at %s", id.Value, first)
+				} else {
+					operationIDs[id.Value] = specPointer(idTokens...)
+				}
+			}
+
+			declared := v.pathParams(op, opTokens, false)
+			for name := range shared {
+				if _, ok := declared[name]; !ok {
+					declared[name] = shared[name]
+				}
+			}
+			v.pathTemplate(path, paths.Content[i], method, declared)
+			v.responses(op, opTokens)
+			v.operationSchemas(op, opTokens)
+		}
+	}
+}
+
+// A declared path parameter, located where it is listed: the parameters entry of an operation or
+// path item, which for a reference is the $ref object
+type declaredParam struct {
+	node   *yaml.Node
+	tokens []string
+}
+
+// Get the path parameters declared on an operation or path item by name, with their locations.
+// Referenced parameters are followed but reported, since the generators do not resolve them.
+func (v *specValidator) pathParams(node *yaml.Node, tokens []string, shared bool) map[string]declaredParam {
+	params := make(map[string]declaredParam)
+	list := mappingValue(node, "parameters")
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return params
+	}
+	listTokens := appendToken(tokens, "parameters")
+	if shared && len(list.Content) > 0 {
+		v.add(SeverityWarning, list, listTokens, "path-level parameters are not supported; declare them on each operation")
+	}
+
+	for i, entry := range list.Content {
+		entry = dealias(entry)
+		param := entry
+		paramTokens := appendToken(listTokens, strconv.Itoa(i))
+		if ref := mappingValue(param, "$ref"); ref != nil {
+			if !shared {
+				v.add(SeverityWarning, ref, appendToken(paramTokens, "$ref"), "parameter references are not supported; the parameter is skipped")
+			}
+			if param = v.resolve(ref.Value); param == nil {
+				continue
+			}
+		}
+		if param.Kind != yaml.MappingNode || scalarValue(param, "in") != "path" {
+			continue
+		}
+		name := scalarValue(param, "name")
+		params[name] = declaredParam{entry, paramTokens}
+		if scalarValue(param, "required") != "true" {
+			v.add(SeverityWarning, entry, paramTokens, "path parameter %q must be required", name)
+		}
+	}
+	return params
+}
+
+// Check that the variables of a path template and the declared path parameters of an operation
+// match. Missing parameters are reported at the path key, extra ones where they are declared.
+func (v *specValidator) pathTemplate(path string, key *yaml.Node, method string, declared map[string]declaredParam) {
+	inTemplate := make(map[string]bool)
+	for _, match := range routeParamPattern.FindAllStringSubmatch(path, -1) {
+		name := match[1]
+		inTemplate[name] = true
+		if _, ok := declared[name]; !ok {
+			v.add(SeverityError, key, []string{"paths", path}, "path template variable {%s} has no path parameter in the %s operation", name, strings.ToUpper(method))
+		}
+	}
+	for _, name := range sortedKeys(declared) {
+		if !inTemplate[name] {
+			param := declared[name]
+			v.add(SeverityError, param.node, param.tokens, "path parameter %q does not appear in the path template %s", name, path)
+		}
+	}
+}
+
+// Check that an operation has responses, and that the success responses with content say what it is
+func (v *specValidator) responses(op *yaml.Node, tokens []string) {
+	responses := mappingValue(op, "responses")
+	if responses == nil || responses.Kind != yaml.MappingNode || len(responses.Content) == 0 {
+		v.add(SeverityError, op, tokens, "operation has no responses")
+		return
+	}
+	responsesTokens := appendToken(tokens, "responses")
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		status, response := responses.Content[i].Value, dealias(responses.Content[i+1])
+		responseTokens := appendToken(responsesTokens, status)
+		if ref := mappingValue(response, "$ref"); ref != nil {
+			v.add(SeverityWarning, ref, appendToken(responseTokens, "$ref"), "response references are not supported; the response has no body")
+			continue
+		}
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		content := mappingValue(response, "content")
+		if content == nil || content.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(content.Content); j += 2 {
+			mediaType, media := content.Content[j].Value, dealias(content.Content[j+1])
+			if mappingValue(media, "schema") == nil {
+				v.add(SeverityWarning, media, appendToken(appendToken(responseTokens, "content"), mediaType), "response %s has no schema; it is typed as any", mediaType)
+			}
+		}
+	}
+}
+
+// Check the schemas of an operation's parameters, request body and responses
+func (v *specValidator) operationSchemas(op *yaml.Node, tokens []string) {
+	if params := mappingValue(op, "parameters"); params != nil && params.Kind == yaml.SequenceNode {
+		for i, param := range params.Content {
+			paramTokens := appendToken(appendToken(tokens, "parameters"), strconv.Itoa(i))
+			v.schema(mappingValue(dealias(param), "schema"), appendToken(paramTokens, "schema"))
+			v.schema(mappingValue(dealias(param), "items"), appendToken(paramTokens, "items"))
+		}
+	}
+	if body := mappingValue(op, "requestBody"); body != nil {
+		bodyTokens := appendToken(tokens, "requestBody")
+		if ref := mappingValue(body, "$ref"); ref != nil {
+			v.add(SeverityWarning, ref, appendToken(bodyTokens, "$ref"), "request body references are not supported; the body is skipped")
+		}
+		v.contentSchemas(body, bodyTokens)
+	}
+	if responses := mappingValue(op, "responses"); responses != nil && responses.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(responses.Content); i += 2 {
+			responseTokens := appendToken(appendToken(tokens, "responses"), responses.Content[i].Value)
+			response := dealias(responses.Content[i+1])
+			v.contentSchemas(response, responseTokens)
+			v.schema(mappingValue(response, "schema"), appendToken(responseTokens, "schema"))
+		}
+	}
+}
+
+func (v *specValidator) contentSchemas(node *yaml.Node, tokens []string) {
+	content := mappingValue(node, "content")
+	if content == nil || content.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(content.Content); i += 2 {
+		mediaTokens := appendToken(appendToken(tokens, "content"), content.Content[i].Value)
+		v.schema(mappingValue(dealias(content.Content[i+1]), "schema"), appendToken(mediaTokens, "schema"))
+	}
+}
+
+func (v *specValidator) componentSchemas() {
+	for _, tokens := range [][]string{{"components", "schemas"}, {"definitions"}} {
+		schemas := v.root
+		for _, token := range tokens {
+			if schemas = mappingValue(schemas, token); schemas == nil {
+				break
+			}
+		}
+		if schemas == nil || schemas.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(schemas.Content); i += 2 {
+			v.schema(schemas.Content[i+1], appendToken(tokens, schemas.Content[i].Value))
+		}
+	}
+}
+
+// Check a schema and the schemas nested in it for references and keywords the generators do not
+// support
+func (v *specValidator) schema(node *yaml.Node, tokens []string) {
+	if node == nil {
+		return
+	}
+	node = dealias(node)
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	if ref := mappingValue(node, "$ref"); ref != nil && strings.HasPrefix(ref.Value, "#") && v.resolve(ref.Value) != nil && !schemaRefPattern.MatchString(ref.Value) {
+		v.add(SeverityWarning, ref, appendToken(tokens, "$ref"), "reference %q is not to a named schema; only #/components/schemas and #/definitions are supported", ref.Value)
+	}
+	for _, keyword := range unsupportedSchemaKeywords {
+		if value := mappingValue(node, keyword); value != nil {
+			v.add(SeverityWarning, value, appendToken(tokens, keyword), "schema keyword %q is not supported and is ignored", keyword)
+		}
+	}
+	if override := mappingValue(node, "x-sveger-type"); override != nil && !isScalarSchema(node) {
+		v.add(SeverityError, override, appendToken(tokens, "x-sveger-type"), "x-sveger-type only applies to string, integer, number and boolean schemas")
+	}
+	if additional := mappingValue(node, "additionalProperties"); additional != nil && dealias(additional).Kind == yaml.MappingNode && len(dealias(additional).Content) > 0 {
+		v.add(SeverityWarning, additional, appendToken(tokens, "additionalProperties"), "additionalProperties schemas are not supported; values are typed as any")
+	}
+
+	if properties := mappingValue(node, "properties"); properties != nil && properties.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(properties.Content); i += 2 {
+			v.schema(properties.Content[i+1], appendToken(appendToken(tokens, "properties"), properties.Content[i].Value))
+		}
+	}
+	v.schema(mappingValue(node, "items"), appendToken(tokens, "items"))
+	for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+		if list := mappingValue(node, keyword); list != nil && list.Kind == yaml.SequenceNode {
+			for i, item := range list.Content {
+				v.schema(item, appendToken(appendToken(tokens, keyword), strconv.Itoa(i)))
+			}
+		}
+	}
+}
+
+// Report whether a schema node describes a scalar: a string, integer, number or boolean that is not
+// composed of other schemas
+func isScalarSchema(node *yaml.Node) bool {
+	for _, keyword := range []string{"$ref", "properties", "items", "allOf", "oneOf", "anyOf"} {
+		if mappingValue(node, keyword) != nil {
+			return false
+		}
+	}
+	switch scalarValue(node, "type") {
+	case "string", "integer", "number", "boolean":
+		return true
+	}
+	return false
+}
+
+// Get the value of a key of a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	node = dealias(node)
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Get the value of a scalar key of a mapping node, or ""
+func scalarValue(node *yaml.Node, key string) string {
+	if value := mappingValue(node, key); value != nil && dealias(value).Kind == yaml.ScalarNode {
+		return dealias(value).Value
+	}
+	return ""
+}
+
+// Follow a YAML alias (*name) to the node it stands for
+func dealias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// Append a token to a copy of a JSON pointer path, so that sibling paths do not share storage
+func appendToken(tokens []string, token string) []string {
+	return append(append(make([]string, 0, len(tokens)+1), tokens...), token)
+}
+
+// Get the 1-based line and column of a byte offset
+func offsetPosition(data []byte, offset int64) (int, int) {
+	line, column := 1, 1
+	for i := int64(0); i < offset-1 && i < int64(len(data)); i++ {
+		if data[i] == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
@@ -0,0 +1,308 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidateSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		spec string
+		want []Diagnostic
+	}{
+		{
+			name: "YAML syntax error",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets: [
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 4, 0, "", "did not find expected node content"},
+			},
+		},
+		{
+			name: "JSON syntax error",
+			file: "spec.json",
+			spec: `{
+  "openapi": "3.0.0",
+  "info": }
+}
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 3, 11, "", "invalid character '}' looking for beginning of value"},
+			},
+		},
+		{
+			name: "JSON type error",
+			file: "spec.json",
+			spec: `{
+  "openapi": "3.0.0",
+  "info": {"title": "t", "version": "1"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": ["listPets"],
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  }
+}
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 7, 24, "#/paths/~1pets/get/operationId", "cannot unmarshal array into string"},
+			},
+		},
+		{
+			name: "YAML type error",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: [listPets]
+      responses:
+        "200": {description: OK}
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 6, 20, "#/paths/~1pets/get/operationId", "cannot unmarshal !!seq into string"},
+			},
+		},
+		{
+			name: "not an object",
+			spec: `- openapi
+- 3.0.0
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 1, 1, "", "the spec is not an object"},
+			},
+		},
+		{
+			name: "no version and no paths",
+			spec: `info: {title: t, version: "1"}
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 1, 1, "#", "the spec has neither an openapi nor a swagger version"},
+				{SeverityWarning, "", 1, 1, "#", "the spec has no paths"},
+			},
+		},
+		{
+			name: "references",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Missing"}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "other.yaml#/Pet"}
+      responses:
+        "204": {description: No content}
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 11, 30, "#/paths/~1pets/get/responses/200/content/application~1json/schema/$ref", `unresolved reference "#/components/schemas/Missing"`},
+				{SeverityError, "", 16, 28, "#/paths/~1pets/post/requestBody/content/application~1json/schema/$ref", `external reference "other.yaml#/Pet" is not supported`},
+			},
+		},
+		{
+			name: "duplicate operationId",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200": {description: OK}
+  /animals:
+    get:
+      operationId: listPets
+      responses:
+        "200": {description: OK}
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 11, 20, "#/paths/~1animals/get/operationId", `duplicate operationId "listPets", first used at #/paths/~1pets/get/operationId`},
+			},
+		},
+		{
+			name: "path parameters",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets/{petId}:
+    parameters:
+      - {name: petId, in: path, required: true, schema: {type: string}}
+    get:
+      responses:
+        "200": {description: OK}
+  /owners/{ownerId}:
+    get:
+      parameters:
+        - $ref: "#/components/parameters/OwnerId"
+      responses:
+        "200": {description: OK}
+  /toys/{toyId}:
+    get:
+      parameters:
+        - {name: toyId, in: path, schema: {type: string}}
+      responses:
+        "200": {description: OK}
+components:
+  parameters:
+    OwnerId: {name: ownerId, in: path, required: true, schema: {type: string}}
+`,
+			want: []Diagnostic{
+				{SeverityWarning, "", 6, 7, "#/paths/~1pets~1{petId}/parameters", "path-level parameters are not supported; declare them on each operation"},
+				{SeverityWarning, "", 13, 17, "#/paths/~1owners~1{ownerId}/get/parameters/0/$ref", "parameter references are not supported; the parameter is skipped"},
+				{SeverityWarning, "", 19, 11, "#/paths/~1toys~1{toyId}/get/parameters/0", `path parameter "toyId" must be required`},
+			},
+		},
+		{
+			name: "path template mismatch",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /owners/{ownerId}:
+    get:
+      responses:
+        "200": {description: OK}
+    delete:
+      parameters:
+        - {name: ownerId, in: path, required: true, schema: {type: string}}
+        - {name: petId, in: path, required: true, schema: {type: string}}
+        - $ref: "#/components/parameters/ToyId"
+      responses:
+        "204": {description: No content}
+components:
+  parameters:
+    ToyId: {name: toyId, in: path, required: true, schema: {type: string}}
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 4, 3, "#/paths/~1owners~1{ownerId}", "path template variable {ownerId} has no path parameter in the GET operation"},
+				{SeverityError, "", 11, 11, "#/paths/~1owners~1{ownerId}/delete/parameters/1", `path parameter "petId" does not appear in the path template /owners/{ownerId}`},
+				{SeverityError, "", 12, 11, "#/paths/~1owners~1{ownerId}/delete/parameters/2", `path parameter "toyId" does not appear in the path template /owners/{ownerId}`},
+				{SeverityWarning, "", 12, 17, "#/paths/~1owners~1{ownerId}/delete/parameters/2/$ref", "parameter references are not supported; the parameter is skipped"},
+			},
+		},
+		{
+			name: "responses",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+    post:
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json: {}
+        default: {$ref: "#/components/responses/Error"}
+    head:
+      responses:
+        "200": {description: OK}
+components:
+  responses:
+    Error: {description: Error}
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 6, 7, "#/paths/~1pets/get", "operation has no responses"},
+				{SeverityWarning, "", 12, 31, "#/paths/~1pets/post/responses/201/content/application~1json", "response application/json has no schema; it is typed as any"},
+				{SeverityWarning, "", 13, 25, "#/paths/~1pets/post/responses/default/$ref", "response references are not supported; the response has no body"},
+				{SeverityWarning, "", 15, 7, "#/paths/~1pets/head", "HEAD operations are not supported and are skipped"},
+			},
+		},
+		{
+			name: "unsupported schemas",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    post:
+      requestBody: {$ref: "#/components/requestBodies/Pet"}
+      responses:
+        "204": {description: No content}
+components:
+  requestBodies:
+    Pet:
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Pet"}
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string, not: {maxLength: 0}}
+        tags:
+          type: object
+          additionalProperties: {type: string}
+        owner: {$ref: "#/components/schemas/Pet/properties/name"}
+`,
+			want: []Diagnostic{
+				{SeverityWarning, "", 6, 27, "#/paths/~1pets/post/requestBody/$ref", "request body references are not supported; the body is skipped"},
+				{SeverityWarning, "", 19, 35, "#/components/schemas/Pet/properties/name/not", `schema keyword "not" is not supported and is ignored`},
+				{SeverityWarning, "", 22, 33, "#/components/schemas/Pet/properties/tags/additionalProperties", "additionalProperties schemas are not supported; values are typed as any"},
+				{SeverityWarning, "", 23, 23, "#/components/schemas/Pet/properties/owner/$ref", `reference "#/components/schemas/Pet/properties/name" is not to a named schema; only #/components/schemas and #/definitions are supported`},
+			},
+		},
+		{
+			name: "x-sveger-type on an object",
+			spec: `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /owners:
+    get:
+      responses:
+        "200": {description: OK}
+components:
+  schemas:
+    OwnerRef:
+      type: object
+      x-sveger-type: OwnerRef
+    Money:
+      type: string
+      x-sveger-type: Money
+`,
+			want: []Diagnostic{
+				{SeverityError, "", 12, 22, "#/components/schemas/OwnerRef/x-sveger-type", "x-sveger-type only applies to string, integer, number and boolean schemas"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := tt.file
+			if file == "" {
+				file = "spec.yaml"
+			}
+			path := filepath.Join(t.TempDir(), file)
+			if err := os.WriteFile(path, []byte(tt.spec), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			diagnostics, err := ValidateSpec(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range diagnostics {
+				diagnostics[i].File = ""
+			}
+			if !reflect.DeepEqual(diagnostics, tt.want) {
+				t.Errorf("ValidateSpec() =\n%v\nwant\n%v", diagnostics, tt.want)
+			}
+		})
+	}
+}
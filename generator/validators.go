@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for validator files
+type ValidatorDef struct {
+	Name string
+	Expr string
+}
+
+type ValidatorsTemplateData struct {
+	Validators []ValidatorDef
+	Imports    []SchemaImport
+}
+
+// validatorContext tracks where a validator expression is rendered
+type validatorContext struct {
+	// prefix is prepended to validator names ("Validators." outside of validator files)
+	prefix string
+	// order is the declaration position of each validator in the current file; references to
+	// validators that are not declared yet are wrapped in v.lazy
+	order   map[string]int
+	current int
+}
+
+// Generate lightweight response validators for every component schema used by a resource
+func generateResponseValidators(api *ir.API, config Config) error {
+	validatorsPath := filepath.Join(config.OutputPath, "validators")
+
+	runtimeTmpl, err := loadTemplate("validators/runtime.tmpl")
+	if err != nil {
+		return err
+	}
+	runtimeContent, err := executeTemplate(runtimeTmpl, struct{}{})
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(validatorsPath, "runtime.ts"), runtimeContent); err != nil {
+		return err
+	}
+
+	tmpl, err := loadTemplate("validators.tmpl")
+	if err != nil {
+		return err
+	}
+
+	owners := schemaOwners(api)
+	resourceNames := make([]string, 0)
+	for _, resource := range api.Resources {
+		resourceName := resource.Name
+		decls := ownedResourceDecls(api, owners, resource)
+		if len(decls) == 0 {
+			continue
+		}
+
+		ordered, order := orderDeclsByDependency(decls)
+		data := ValidatorsTemplateData{Imports: foreignSchemaImports(decls, owners, resourceName)}
+		for _, imp := range data.Imports {
+			for i, name := range imp.Names {
+				imp.Names[i] = validatorName(name)
+			}
+		}
+		for i, name := range ordered {
+			ctx := &validatorContext{order: order, current: i}
+			data.Validators = append(data.Validators, ValidatorDef{
+				Name: validatorName(name),
+				Expr: typeToValidator(decls[name].Type, ctx, 0),
+			})
+		}
+
+		content, err := executeTemplate(tmpl, data)
+		if err != nil {
+			return err
+		}
+
+		resourceLower := strings.ToLower(resourceName)
+		filePath, err := outputPath(validatorsPath, fileSegment(resourceLower)+".validators.ts")
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		resourceNames = append(resourceNames, resourceLower)
+	}
+
+	var index strings.Builder
+	index.WriteString("// Auto-generated validators index\n\n")
+	index.WriteString("export { ResponseValidationError, isResponseValidationError, validateResponse } from './runtime';\n")
+	index.WriteString("export type { Validator, ValidationOptions } from './runtime';\n")
+	sort.Strings(resourceNames)
+	for _, name := range resourceNames {
+		index.WriteString(fmt.Sprintf("export * from './%s.validators';\n", name))
+	}
+
+	return writeFile(filepath.Join(validatorsPath, "index.ts"), index.String())
+}
+
+func validatorName(typeName string) string {
+	return "validate" + toTitleCase(typeName)
+}
+
+// Convert a type to a validator expression built from the runtime combinators
+func typeToValidator(t *ir.Type, ctx *validatorContext, indent int) string {
+	if t == nil {
+		return "v.any()"
+	}
+
+	expr := baseTypeToValidator(t, ctx, indent)
+	if t.Nullable && expr != "v.any()" {
+		expr = fmt.Sprintf("v.nullable(%s)", expr)
+	}
+	return expr
+}
+
+func baseTypeToValidator(t *ir.Type, ctx *validatorContext, indent int) string {
+	if t.Kind == ir.Ref {
+		ref := ctx.prefix + validatorName(t.Ref)
+		if ctx.order != nil {
+			if pos, ok := ctx.order[t.Ref]; !ok || pos >= ctx.current {
+				return fmt.Sprintf("v.lazy(() => %s)", ref)
+			}
+		}
+		return ref
+	}
+
+	if len(t.Enum) > 0 {
+		literals := make([]string, 0, len(t.Enum))
+		for _, val := range t.Enum {
+			literals = append(literals, jsLiteral(val))
+		}
+		return fmt.Sprintf("v.oneOfValues([%s])", strings.Join(literals, ", "))
+	}
+
+	switch t.Kind {
+	case ir.Intersection:
+		return fmt.Sprintf("v.allOf(%s)", validatorList(t.Variants, ctx, indent))
+	case ir.Union:
+		if len(t.Variants) == 1 {
+			return typeToValidator(t.Variants[0], ctx, indent)
+		}
+		return fmt.Sprintf("v.anyOf(%s)", validatorList(t.Variants, ctx, indent))
+	case ir.String:
+		var constraints []string
+		if t.Format != "" {
+			constraints = append(constraints, "format: "+jsString(t.Format))
+		}
+		if t.MinLength != nil {
+			constraints = append(constraints, fmt.Sprintf("minLength: %d", *t.MinLength))
+		}
+		if t.MaxLength != nil {
+			constraints = append(constraints, fmt.Sprintf("maxLength: %d", *t.MaxLength))
+		}
+		if t.Pattern != "" {
+			constraints = append(constraints, "pattern: "+jsString(t.Pattern))
+		}
+		return "v.string(" + constraintObject(constraints) + ")"
+	case ir.Integer, ir.Number:
+		var constraints []string
+		if t.Kind == ir.Integer {
+			constraints = append(constraints, "integer: true")
+		}
+		if t.Minimum != nil {
+			constraints = append(constraints, "minimum: "+formatNumber(*t.Minimum))
+		}
+		if t.Maximum != nil {
+			constraints = append(constraints, "maximum: "+formatNumber(*t.Maximum))
+		}
+		return "v.number(" + constraintObject(constraints) + ")"
+	case ir.Boolean:
+		return "v.boolean()"
+	case ir.Array:
+		var constraints []string
+		if t.MinItems != nil {
+			constraints = append(constraints, fmt.Sprintf("minItems: %d", *t.MinItems))
+		}
+		if t.MaxItems != nil {
+			constraints = append(constraints, fmt.Sprintf("maxItems: %d", *t.MaxItems))
+		}
+		item := typeToValidator(t.Items, ctx, indent)
+		if len(constraints) > 0 {
+			return fmt.Sprintf("v.array(%s, %s)", item, constraintObject(constraints))
+		}
+		return fmt.Sprintf("v.array(%s)", item)
+	case ir.Object:
+		return objectToValidator(t, ctx, indent)
+	}
+
+	return "v.any()"
+}
+
+func objectToValidator(t *ir.Type, ctx *validatorContext, indent int) string {
+	pad := strings.Repeat("  ", indent+1)
+	var b strings.Builder
+	b.WriteString("v.object({\n")
+	for _, f := range t.Fields {
+		expr := typeToValidator(f.Type, ctx, indent+1)
+		if !f.Required {
+			expr = fmt.Sprintf("v.optional(%s)", expr)
+		}
+		b.WriteString(fmt.Sprintf("%s%s: %s,\n", pad, propertyKey(f.Name), expr))
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "})")
+	return b.String()
+}
+
+func validatorList(types []*ir.Type, ctx *validatorContext, indent int) string {
+	parts := make([]string, 0, len(types))
+	for _, t := range types {
+		parts = append(parts, typeToValidator(t, ctx, indent))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func constraintObject(constraints []string) string {
+	if len(constraints) == 0 {
+		return ""
+	}
+	return "{ " + strings.Join(constraints, ", ") + " }"
+}
+
+// Build the validator expression used to check an operation's response type, or "" if it has none
+func getResponseValidator(t *ir.Type) string {
+	if t == nil {
+		return ""
+	}
+	expr := typeToValidator(t, &validatorContext{prefix: "Validators."}, 0)
+	if expr == "v.any()" {
+		return ""
+	}
+	return expr
+}
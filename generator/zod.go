@@ -0,0 +1,353 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/velogo-dev/sveger/generator/ir"
+)
+
+// Template data for zod schema files
+type ZodSchemaDef struct {
+	Name string
+	Expr string
+	// Annotation is the explicit type of a schema containing z.lazy, which TypeScript cannot infer
+	Annotation string
+}
+
+type ZodTemplateData struct {
+	Schemas []ZodSchemaDef
+	Imports []SchemaImport
+}
+
+// zodContext tracks where a zod expression is rendered
+type zodContext struct {
+	config Config
+	// prefix is prepended to schema constant names ("Schemas." outside of schema files)
+	prefix string
+	// order is the emission position of each schema in the current file; references to
+	// schemas that are not emitted yet are wrapped in z.lazy
+	order   map[string]int
+	current int
+	lazy    bool
+}
+
+// Generate zod schemas alongside the TypeScript types
+func generateZodSchemas(api *ir.API, config Config) error {
+	schemasPath := filepath.Join(config.OutputPath, "schemas")
+
+	tmpl, err := loadTemplate("schemas.tmpl")
+	if err != nil {
+		return err
+	}
+
+	owners := schemaOwners(api)
+	resourceNames := make([]string, 0)
+	for _, resource := range api.Resources {
+		resourceName := resource.Name
+		decls := ownedResourceDecls(api, owners, resource)
+		if len(decls) == 0 {
+			continue
+		}
+
+		data := ZodTemplateData{
+			Schemas: buildZodSchemaDefs(decls, config),
+			Imports: foreignSchemaImports(decls, owners, resourceName),
+		}
+
+		content, err := executeTemplate(tmpl, data)
+		if err != nil {
+			return err
+		}
+
+		resourceLower := strings.ToLower(resourceName)
+		filePath, err := outputPath(schemasPath, fileSegment(resourceLower)+".schemas.ts")
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filePath, content); err != nil {
+			return err
+		}
+		resourceNames = append(resourceNames, resourceLower)
+	}
+
+	var index strings.Builder
+	index.WriteString("// Auto-generated schemas index\n\n")
+	sort.Strings(resourceNames)
+	for _, name := range resourceNames {
+		index.WriteString(fmt.Sprintf("export * from './%s.schemas';\n", name))
+	}
+
+	return writeFile(filepath.Join(schemasPath, "index.ts"), index.String())
+}
+
+// Build zod schema definitions in dependency order
+func buildZodSchemaDefs(decls map[string]*ir.Decl, config Config) []ZodSchemaDef {
+	ordered, order := orderDeclsByDependency(decls)
+
+	defs := make([]ZodSchemaDef, 0, len(ordered))
+	for i, name := range ordered {
+		ctx := &zodContext{config: config, order: order, current: i}
+		t := decls[name].Type
+
+		var expr string
+		if enumMembers(t, config) != nil && config.EnumStyle != EnumStyleConstEnum && isEnumObjectCompatible(t.Enum) {
+			// Named enums are validated against the generated enum object; const enums have none at runtime
+			expr = fmt.Sprintf("z.nativeEnum(Types.%s)", name)
+		} else {
+			expr = typeToZod(t, ctx, 0)
+		}
+
+		def := ZodSchemaDef{Name: name, Expr: expr}
+		if ctx.lazy {
+			def.Annotation = fmt.Sprintf("z.ZodType<Types.%s>", name)
+			if config.PropertyNaming == PropertyNamingCamel {
+				// The input is the wire format, which no longer matches the camelCase types
+				def.Annotation = fmt.Sprintf("z.ZodType<Types.%s, z.ZodTypeDef, unknown>", name)
+			}
+		}
+		defs = append(defs, def)
+	}
+
+	return defs
+}
+
+// Order declarations so dependencies are declared before their dependents. Returns the declaration
+// order and each name's position in it.
+func orderDeclsByDependency(decls map[string]*ir.Decl) ([]string, map[string]int) {
+	// Depth-first post-order; references closing a cycle point forward and must be lazy
+	order := make(map[string]int)
+	ordered := make([]string, 0, len(decls))
+	visiting := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if _, done := order[name]; done || visiting[name] {
+			return
+		}
+		visiting[name] = true
+		for _, dep := range typeRefs(decls[name].Type) {
+			if _, ok := decls[dep]; ok {
+				visit(dep)
+			}
+		}
+		visiting[name] = false
+		order[name] = len(ordered)
+		ordered = append(ordered, name)
+	}
+	for _, name := range sortedKeys(decls) {
+		visit(name)
+	}
+
+	return ordered, order
+}
+
+// Convert a type to a zod expression
+func typeToZod(t *ir.Type, ctx *zodContext, indent int) string {
+	if t == nil {
+		return "z.any()"
+	}
+
+	expr := baseTypeToZod(t, ctx, indent)
+	if tsType := mappedType(t, ctx.config); isScalarType(tsType) {
+		// Brand the validated wire value as the generated scalar type
+		expr = fmt.Sprintf("%s.transform((value) => value as Types.%s)", expr, tsType)
+	}
+	if t.Nullable && expr != "z.any()" {
+		expr += ".nullable()"
+	}
+	return expr
+}
+
+func baseTypeToZod(t *ir.Type, ctx *zodContext, indent int) string {
+	if t.Kind == ir.Ref {
+		ref := ctx.prefix + t.Ref + "Schema"
+		if ctx.order != nil {
+			if pos, ok := ctx.order[t.Ref]; !ok || pos >= ctx.current {
+				ctx.lazy = true
+				return fmt.Sprintf("z.lazy(() => %s)", ref)
+			}
+		}
+		return ref
+	}
+
+	// Mapped types are coerced from their wire representation
+	switch tsType := mappedType(t, ctx.config); {
+	case tsType == "Date":
+		return "z.coerce.date()"
+	case tsType == "bigint":
+		return "z.coerce.bigint()"
+	case tsType == "string" && (t.Kind == ir.Integer || t.Kind == ir.Number):
+		return "z.coerce.string()"
+	case tsType != "" && !isScalarType(tsType):
+		return "z.any()"
+	}
+
+	if len(t.Enum) > 0 {
+		return enumToZod(t.Enum)
+	}
+
+	switch t.Kind {
+	case ir.Intersection:
+		parts := make([]string, 0, len(t.Variants))
+		for _, variant := range t.Variants {
+			parts = append(parts, typeToZod(variant, ctx, indent))
+		}
+		expr := parts[0]
+		for _, part := range parts[1:] {
+			expr = fmt.Sprintf("%s.and(%s)", expr, part)
+		}
+		return expr
+	case ir.Union:
+		parts := make([]string, 0, len(t.Variants))
+		for _, variant := range t.Variants {
+			parts = append(parts, typeToZod(variant, ctx, indent))
+		}
+		if len(parts) == 1 {
+			return parts[0]
+		}
+		return fmt.Sprintf("z.union([%s])", strings.Join(parts, ", "))
+	case ir.String:
+		return "z.string()" + stringFormatToZod(t.Format) + stringConstraintsToZod(t)
+	case ir.Integer:
+		return "z.number().int()" + numberConstraintsToZod(t)
+	case ir.Number:
+		return "z.number()" + numberConstraintsToZod(t)
+	case ir.Boolean:
+		return "z.boolean()"
+	case ir.Array:
+		expr := fmt.Sprintf("z.array(%s)", typeToZod(t.Items, ctx, indent))
+		if t.MinItems != nil {
+			expr += fmt.Sprintf(".min(%d)", *t.MinItems)
+		}
+		if t.MaxItems != nil {
+			expr += fmt.Sprintf(".max(%d)", *t.MaxItems)
+		}
+		return expr
+	case ir.Object:
+		return objectToZod(t, ctx, indent)
+	}
+
+	return "z.any()"
+}
+
+func objectToZod(t *ir.Type, ctx *zodContext, indent int) string {
+	pad := strings.Repeat("  ", indent+1)
+	var b strings.Builder
+	b.WriteString("z.object({\n")
+	for _, f := range t.Fields {
+		expr := typeToZod(f.Type, ctx, indent+1)
+		if !f.Required {
+			expr += ".optional()"
+		}
+		b.WriteString(fmt.Sprintf("%s%s: %s,\n", pad, propertyKey(f.Name), expr))
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "})")
+
+	// The schema parses the wire format; with camelCase naming its output is renamed to the
+	// TypeScript properties so that z.infer matches the generated interface
+	names := fieldNames(t.Fields, ctx.config)
+	renamed := false
+	for wire, name := range names {
+		renamed = renamed || wire != name
+	}
+	if !renamed {
+		return b.String()
+	}
+	b.WriteString(".transform((value) => ({\n")
+	for _, f := range t.Fields {
+		b.WriteString(fmt.Sprintf("%s%s: %s,\n", pad, propertyKey(names[f.Name]), propertyAccess("value", f.Name)))
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "}))")
+	return b.String()
+}
+
+// Render a property read, with bracket notation for names that are not identifiers
+func propertyAccess(object, name string) string {
+	if key := propertyKey(name); key == name {
+		return object + "." + name
+	}
+	return object + "[" + jsString(name) + "]"
+}
+
+func enumToZod(values []interface{}) string {
+	allStrings := true
+	literals := make([]string, 0, len(values))
+	for _, val := range values {
+		if _, ok := val.(string); !ok {
+			allStrings = false
+		}
+		literals = append(literals, jsLiteral(val))
+	}
+
+	if allStrings {
+		return fmt.Sprintf("z.enum([%s])", strings.Join(literals, ", "))
+	}
+	if len(literals) == 1 {
+		return fmt.Sprintf("z.literal(%s)", literals[0])
+	}
+	parts := make([]string, 0, len(literals))
+	for _, literal := range literals {
+		parts = append(parts, fmt.Sprintf("z.literal(%s)", literal))
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(parts, ", "))
+}
+
+func stringFormatToZod(format string) string {
+	switch format {
+	case "email":
+		return ".email()"
+	case "uuid":
+		return ".uuid()"
+	case "uri", "url":
+		return ".url()"
+	case "date-time":
+		return ".datetime({ offset: true })"
+	case "date":
+		return ".date()"
+	case "ipv4":
+		return ".ip({ version: 'v4' })"
+	case "ipv6":
+		return ".ip({ version: 'v6' })"
+	default:
+		return ""
+	}
+}
+
+func stringConstraintsToZod(t *ir.Type) string {
+	var b strings.Builder
+	if t.MinLength != nil {
+		b.WriteString(fmt.Sprintf(".min(%d)", *t.MinLength))
+	}
+	if t.MaxLength != nil {
+		b.WriteString(fmt.Sprintf(".max(%d)", *t.MaxLength))
+	}
+	if t.Pattern != "" {
+		b.WriteString(fmt.Sprintf(".regex(new RegExp(%s))", jsString(t.Pattern)))
+	}
+	return b.String()
+}
+
+func numberConstraintsToZod(t *ir.Type) string {
+	var b strings.Builder
+	if t.Minimum != nil {
+		b.WriteString(fmt.Sprintf(".min(%s)", formatNumber(*t.Minimum)))
+	}
+	if t.Maximum != nil {
+		b.WriteString(fmt.Sprintf(".max(%s)", formatNumber(*t.Maximum)))
+	}
+	return b.String()
+}
+
+// Build the zod expression used to validate an operation's response type, or "" if it has none
+func getResponseZodSchema(t *ir.Type, config Config) string {
+	if t == nil {
+		return ""
+	}
+	expr := typeToZod(t, &zodContext{config: config, prefix: "Schemas."}, 0)
+	if expr == "z.any()" {
+		return ""
+	}
+	return expr
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const snakeCaseSpec = `openapi: 3.0.0
+info: {title: Users, version: "1"}
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      tags: [users]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+components:
+  schemas:
+    User:
+      type: object
+      required: [user_name]
+      properties:
+        user_name: {type: string}
+`
+
+func TestZodValidateConvertsOnce(t *testing.T) {
+	inputPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(inputPath, []byte(snakeCaseSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config := Config{
+		InputPath:      inputPath,
+		Language:       "typescript",
+		Zod:            true,
+		ZodValidate:    true,
+		GroupBy:        GroupByTag,
+		PropertyNaming: PropertyNamingCamel,
+		EnumStyle:      EnumStyleAuto,
+	}
+	files := generateFiles(t, config, GenerateTypeScript)
+
+	// The zod schema renames user_name, so the operation must not decode the result again
+	assertContains(t, files, "schemas/users.schemas.ts", "userName: value.user_name")
+	assertContains(t, files, "resources/users/operations/get-user.ts",
+		"return Schemas.UserSchema.parse(response.data) as Types.User;")
+	if content := files["resources/users/operations/get-user.ts"]; strings.Contains(content, "c.decode") {
+		t.Errorf("get-user.ts decodes the zod output again:\n%s", content)
+	}
+}
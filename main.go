@@ -4,21 +4,57 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/velogo-dev/sveger/generator"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "mock" {
+		runMock(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
+	}
+
 	var (
 		inputPath        = flag.String("input", "", "Path to OpenAPI spec file (required)")
 		outputPath       = flag.String("output", "./generated", "Output directory")
-		language         = flag.String("lang", "typescript", "Target language (typescript)")
+		language         = flag.String("lang", "typescript", "Target language (typescript, go, go-server, python, dart)")
 		splitFiles       = flag.Bool("split", true, "Split files (one endpoint per file)")
 		useAxios         = flag.Bool("axios", true, "Generate Axios integration")
 		baseURL          = flag.String("base-url", "", "Base URL (optional, will use spec URL if not provided)")
 		timeout          = flag.String("timeout", "10000", "Request timeout in milliseconds")
 		authType         = flag.String("auth", "bearer", "Authentication type (bearer, apikey, basic, none)")
 		withInterceptors = flag.Bool("interceptors", false, "Include request/response interceptors")
+		svelteKit        = flag.Bool("sveltekit", false, "Generate SvelteKit helpers (createServerApi, withApi)")
+		zod              = flag.Bool("zod", false, "Generate zod schemas alongside TypeScript types")
+		zodValidate      = flag.Bool("zod-validate", false, "Validate responses with the generated zod schemas (requires -zod)")
+		validateResp     = flag.Bool("validate-responses", false, "Generate lightweight response validators and check responses in operations")
+		constraints      = flag.Bool("constraints", false, "Export per-schema constraint metadata objects for form validation")
+		excludeDepr      = flag.Bool("exclude-deprecated", false, "Leave deprecated operations out of the generated client")
+		includeTags      = flag.String("include-tags", "", "Only generate operations with one of these tags (comma-separated)")
+		excludeTags      = flag.String("exclude-tags", "", "Skip operations with any of these tags (comma-separated)")
+		includePaths     = flag.String("include-paths", "", "Only generate operations whose path matches one of these globs (comma-separated, ** spans segments)")
+		includeOps       = flag.String("include-ops", "", "Only generate these operationIds (comma-separated)")
+		groupBy          = flag.String("group-by", "tag", "Resource grouping strategy (tag, tags, path, extension, flat)")
+		pathSegment      = flag.Int("path-segment", 0, "Path segment naming the resource with -group-by path (0-based, after -strip-prefix)")
+		stripPrefix      = flag.String("strip-prefix", "", "Path prefix removed before picking the resource segment (e.g. /api/v1)")
+		renameResources  = flag.String("rename-resources", "", "Resource renames (e.g. user-accounts=users,default=api)")
+		propertyNaming   = flag.String("property-naming", "original", "Property naming (original, camel); camel generates mappers to and from the wire names")
+		goPackage        = flag.String("go-package", "", "Package name for -lang go and go-server (default: output directory name)")
+		enumStyle        = flag.String("enum-style", "auto", "Enum output (auto, union, enum, const-enum, const-object); auto emits enums only with x-enum-varnames")
+		typeMap          = flag.String("type-map", "", "Format to TypeScript type mappings (e.g. date-time=Date,int64=bigint,uuid=UUID,binary=Blob)")
+		server           = flag.String("server", "", "Also generate typed route handlers for this framework (hono, express) in server/")
+		mocks            = flag.Bool("mocks", false, "Also generate MSW request handlers answering with spec examples in mocks/")
+		factories        = flag.Bool("factories", false, "Also generate seeded build<Schema> data builders in factories/")
+		skipValidation   = flag.Bool("skip-validation", false, "Generate even when validating the spec finds errors")
+		dumpIR           = flag.String("dump-ir", "", "Also write the intermediate model the generators work from as JSON to this file")
 	)
 
 	flag.Parse()
@@ -28,16 +64,59 @@ func main() {
 		log.Fatal("Error: input path is required")
 	}
 
+	typeMappings, err := generator.ParseTypeMappings(*typeMap)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	resourceRenames, err := generator.ParseResourceRenames(*renameResources)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := generator.ValidateGroupBy(*groupBy); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := generator.ValidatePropertyNaming(*propertyNaming); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := generator.ValidateEnumStyle(*enumStyle); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if err := generator.ValidateServer(*server); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
 	config := generator.Config{
-		InputPath:        *inputPath,
-		OutputPath:       *outputPath,
-		Language:         *language,
-		SplitFiles:       *splitFiles,
-		UseAxios:         *useAxios,
-		BaseURL:          *baseURL,
-		Timeout:          *timeout,
-		AuthType:         *authType,
-		WithInterceptors: *withInterceptors,
+		InputPath:         *inputPath,
+		OutputPath:        *outputPath,
+		Language:          *language,
+		SplitFiles:        *splitFiles,
+		UseAxios:          *useAxios,
+		BaseURL:           *baseURL,
+		Timeout:           *timeout,
+		AuthType:          *authType,
+		WithInterceptors:  *withInterceptors,
+		SvelteKit:         *svelteKit,
+		Zod:               *zod,
+		ZodValidate:       *zodValidate,
+		ValidateResponses: *validateResp,
+		Constraints:       *constraints,
+		TypeMappings:      typeMappings,
+		ExcludeDeprecated: *excludeDepr,
+		IncludeTags:       splitList(*includeTags),
+		ExcludeTags:       splitList(*excludeTags),
+		IncludePaths:      splitList(*includePaths),
+		IncludeOps:        splitList(*includeOps),
+		GroupBy:           *groupBy,
+		PathSegment:       *pathSegment,
+		StripPrefix:       *stripPrefix,
+		ResourceRenames:   resourceRenames,
+		PropertyNaming:    *propertyNaming,
+		EnumStyle:         *enumStyle,
+		GoPackage:         *goPackage,
+		Server:            *server,
+		Mocks:             *mocks,
+		Factories:         *factories,
+		DumpIR:            *dumpIR,
 	}
 
 	fmt.Printf("Generating API client...\n")
@@ -52,16 +131,172 @@ func main() {
 		fmt.Printf("Auth Type: %s\n", config.AuthType)
 		fmt.Printf("With Interceptors: %t\n", config.WithInterceptors)
 	}
+	fmt.Printf("SvelteKit: %t\n", config.SvelteKit)
+	fmt.Printf("Zod: %t\n", config.Zod)
+	fmt.Printf("Validate responses: %t\n", config.ValidateResponses)
+	fmt.Printf("Exclude deprecated: %t\n", config.ExcludeDeprecated)
+	fmt.Printf("Group by: %s\n", config.GroupBy)
+
+	if !*skipValidation {
+		diagnostics, err := generator.ValidateSpec(config.InputPath)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		printDiagnostics(diagnostics)
+		if generator.HasErrors(diagnostics) {
+			log.Fatal("Error: the spec is invalid (use -skip-validation to generate anyway)")
+		}
+	}
+
+	if config.ZodValidate && !config.Zod {
+		log.Fatal("Error: -zod-validate requires -zod")
+	}
+	if config.ZodValidate && config.ValidateResponses {
+		log.Fatal("Error: -zod-validate and -validate-responses are mutually exclusive")
+	}
 
 	switch config.Language {
 	case "typescript":
-		err := generator.GenerateTypeScript(config)
+		report, err := generator.GenerateTypeScript(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printReport(report)
+	case "go":
+		report, err := generator.GenerateGo(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printReport(report)
+	case "go-server":
+		report, err := generator.GenerateGoServer(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printReport(report)
+	case "python":
+		report, err := generator.GeneratePython(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printReport(report)
+	case "dart":
+		report, err := generator.GenerateDart(config)
 		if err != nil {
 			log.Fatal(err)
 		}
+		printReport(report)
 	default:
 		log.Fatalf("unsupported language: %s", config.Language)
 	}
 
 	fmt.Println("✅ API client generated successfully!")
-}
\ No newline at end of file
+}
+
+// Serve the operations of a spec with example responses: sveger mock -input spec.yaml -port 4010
+func runMock(args []string) {
+	flags := flag.NewFlagSet("mock", flag.ExitOnError)
+	var (
+		inputPath = flags.String("input", "", "Path to OpenAPI spec file (required)")
+		host      = flags.String("host", "127.0.0.1", "Address to listen on")
+		port      = flags.Int("port", 4010, "Port to listen on")
+	)
+	flags.Parse(args)
+
+	if *inputPath == "" {
+		flags.Usage()
+		log.Fatal("Error: input path is required")
+	}
+
+	server, err := generator.NewMockServer(generator.Config{InputPath: *inputPath})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	address := net.JoinHostPort(*host, strconv.Itoa(*port))
+	fmt.Printf("Mock server for %s listening on http://%s\n", *inputPath, address)
+	for _, route := range server.Routes() {
+		fmt.Printf("  %s\n", route)
+	}
+	log.Fatal(http.ListenAndServe(address, server))
+}
+
+// Check a spec without generating: sveger validate -input spec.yaml. Exits with 1 when the spec has
+// errors (or warnings with -strict) and 2 when it cannot be read.
+func runValidate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	var (
+		inputPath = flags.String("input", "", "Path to OpenAPI spec file (required)")
+		strict    = flags.Bool("strict", false, "Fail on warnings too")
+	)
+	flags.Parse(args)
+
+	if *inputPath == "" {
+		flags.Usage()
+		fmt.Fprintln(os.Stderr, "Error: input path is required")
+		return 2
+	}
+
+	diagnostics, err := generator.ValidateSpec(*inputPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	printDiagnostics(diagnostics)
+
+	errors := 0
+	for _, d := range diagnostics {
+		if d.Severity == generator.SeverityError {
+			errors++
+		}
+	}
+	warnings := len(diagnostics) - errors
+	fmt.Printf("%s: %d error(s), %d warning(s)\n", *inputPath, errors, warnings)
+	if errors > 0 || (*strict && warnings > 0) {
+		return 1
+	}
+	return 0
+}
+
+// Print validation diagnostics to stderr, one per line
+func printDiagnostics(diagnostics []generator.Diagnostic) {
+	for _, d := range diagnostics {
+		fmt.Fprintln(os.Stderr, d)
+	}
+}
+
+// Split a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Print the generation summary
+func printReport(report *generator.Report) {
+	if report.FilteredOperations > 0 {
+		fmt.Printf("Filtered out %d operation(s)\n", report.FilteredOperations)
+	}
+	if report.PrunedSchemas > 0 {
+		fmt.Printf("Pruned %d unreachable schema(s)\n", report.PrunedSchemas)
+	}
+	if report.ExcludedDeprecated > 0 {
+		fmt.Printf("Excluded %d deprecated operation(s)\n", report.ExcludedDeprecated)
+	}
+	if len(report.Renames) > 0 {
+		fmt.Printf("Renamed %d identifier(s):\n", len(report.Renames))
+		for _, rename := range report.Renames {
+			fmt.Printf("  - %s\n", rename)
+		}
+	}
+	if len(report.Deprecated) > 0 {
+		fmt.Printf("⚠️  %d deprecated item(s) in the generated client:\n", len(report.Deprecated))
+		for _, item := range report.Deprecated {
+			fmt.Printf("  - %s\n", item)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunValidateExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, spec string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	valid := write("valid.yaml", `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200": {description: OK}
+`)
+	withWarning := write("warning.yaml", `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200": {description: OK}
+    head:
+      responses:
+        "200": {description: OK}
+`)
+	withError := write("error.yaml", `openapi: 3.0.0
+info: {title: t, version: "1"}
+paths:
+  /pets/{petId}:
+    get:
+      responses:
+        "200": {description: OK}
+`)
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"valid", []string{"-input", valid}, 0},
+		{"warnings", []string{"-input", withWarning}, 0},
+		{"warnings with -strict", []string{"-strict", "-input", withWarning}, 1},
+		{"errors", []string{"-input", withError}, 1},
+		{"unreadable file", []string{"-input", filepath.Join(dir, "missing.yaml")}, 2},
+		{"no input", nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runValidate(tt.args); got != tt.want {
+				t.Errorf("runValidate(%q) = %d, want %d", tt.args, got, tt.want)
+			}
+		})
+	}
+}